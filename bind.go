@@ -0,0 +1,57 @@
+package httprouter
+
+import (
+	"github.com/makasim/httprouter/internal/param"
+	"github.com/valyala/fasthttp"
+)
+
+// Bind sets fields of the struct dst points to from params. A field is bound to the param named by its path tag:
+//
+//	type getOrder struct {
+//		UserID  int64  `path:"user_id"`
+//		OrderID string `path:"id"`
+//	}
+//
+// A wildcard param is found by its name with or without the leading asterisk.
+// Use BindCtx to bind params of the request matched by Router.Handle.
+// Fields may be strings, bools, ints, uints, floats, or implement encoding.TextUnmarshaler.
+// Bind sets every field it can and returns the errors of the others joined, each one is a *ParamError.
+func Bind(ps Params, dst interface{}) error {
+	return param.Bind(bindParams(ps), dst)
+}
+
+// BindCtx works like Bind with params of the route matched by Router.Handle,
+// stored as user values or as RequestParams when Router.PooledParams is enabled.
+// Unless Router.CopyParams is enabled, string fields reference the request path and must not be kept after the handler returns.
+func BindCtx(ctx *fasthttp.RequestCtx, dst interface{}) error {
+	return param.Bind(bindCtx{ctx: ctx}, dst)
+}
+
+// CheckBind reports whether values of v's struct type can be bound from params of pattern:
+// every path tagged field must have a supported type and name a param of pattern.
+// Call it when registering a route which handler uses Bind to fail early instead of on every request.
+func CheckBind(pattern string, v interface{}) error {
+	return param.CheckBind(pattern, v)
+}
+
+type bindParams Params
+
+func (ps bindParams) Get(name string) string {
+	if v := Params(ps).Get(name); v != "" {
+		return v
+	}
+
+	return Params(ps).Get("*" + name)
+}
+
+type bindCtx struct {
+	ctx *fasthttp.RequestCtx
+}
+
+func (b bindCtx) Get(name string) string {
+	if v := paramValue(b.ctx, name); v != "" {
+		return v
+	}
+
+	return paramValue(b.ctx, "*"+name)
+}
@@ -0,0 +1,89 @@
+package httprouter_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/makasim/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// the binder is tested in internal/param, these tests cover the router side
+
+func TestBind(main *testing.T) {
+	main.Run("Wildcard", func(t *testing.T) {
+		var in struct {
+			Path string `path:"path"`
+		}
+
+		require.NoError(t, httprouter.Bind(httprouter.Params{{Key: "*path", Value: "a/b.txt"}}, &in))
+		require.Equal(t, "a/b.txt", in.Path)
+	})
+
+	main.Run("Errors", func(t *testing.T) {
+		var in struct {
+			ID int `path:"id"`
+		}
+
+		err := httprouter.Bind(httprouter.Params{}, &in)
+		require.ErrorIs(t, err, httprouter.ErrParamMissing)
+
+		var paramErr *httprouter.ParamError
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, "id", paramErr.Name)
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		type req struct {
+			UserID int64  `path:"user_id"`
+			ID     string `path:"id"`
+		}
+		ps := httprouter.Params{{Key: "user_id", Value: "42"}, {Key: "id", Value: "abc"}}
+
+		var in req
+		require.NoError(t, httprouter.Bind(ps, &in))
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			_ = httprouter.Bind(ps, &in)
+		}))
+	})
+}
+
+func TestBindCtx(main *testing.T) {
+	for name, pooled := range map[string]bool{"UserValues": false, "PooledParams": true} {
+		pooled := pooled
+
+		main.Run(name, func(t *testing.T) {
+			r := httprouter.New()
+			r.PooledParams = pooled
+			require.NoError(t, r.RegisterHandler("GET", "/users/{user_id}/files/{*path}", func(ctx *fasthttp.RequestCtx) {
+				var in struct {
+					UserID int    `path:"user_id"`
+					Path   string `path:"path"`
+				}
+				if err := httprouter.BindCtx(ctx, &in); err != nil {
+					r.HandleParamError(ctx, err)
+					return
+				}
+
+				ctx.SetBodyString(strconv.Itoa(in.UserID) + ":" + in.Path)
+			}))
+
+			require.Equal(t, "1:a/b.txt", string(serve(r, "GET", "/users/1/files/a/b.txt").Response.Body()))
+
+			ctx := serve(r, "GET", "/users/x/files/a/b.txt")
+			require.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+			require.Equal(t, `param "user_id": invalid int "x": invalid syntax`, string(ctx.Response.Body()))
+		})
+	}
+}
+
+func TestCheckBind(t *testing.T) {
+	type req struct {
+		UserID  int    `path:"user_id"`
+		OrderID string `path:"id"`
+	}
+
+	require.NoError(t, httprouter.CheckBind("/users/{user_id}/orders/{id}", req{}))
+	require.EqualError(t, httprouter.CheckBind("/users/{user_id}", &req{}), `bind httprouter_test.req: field OrderID: param "id" not in pattern "/users/{user_id}"`)
+}
@@ -0,0 +1,94 @@
+package httprouter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/makasim/httprouter/radix"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrorRequestHandler is a request handler that returns an error instead of writing an error response.
+// Register it with RegisterErrorHandler.
+type ErrorRequestHandler func(*fasthttp.RequestCtx) error
+
+// RegisterErrorHandler works like RegisterHandler for a handler that returns an error.
+// A returned error is passed to ErrorHandler along with the route Handle matched,
+// which differs from method and path when the handler id is registered for other routes too.
+// Without ErrorHandler a *ParamError goes to HandleParamError, any other error gets 500 Internal Server Error.
+func (r *Router) RegisterErrorHandler(method, path string, handler ErrorRequestHandler, middleware ...Middleware) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+
+	pattern, err := r.convertPath(path)
+	if err != nil {
+		return err
+	}
+
+	var hID uint64
+	hID = r.AddHandler(func(ctx *fasthttp.RequestCtx) {
+		if err := handler(ctx); err != nil {
+			route, ok := r.matchedRoute(ctx)
+			if !ok {
+				// the handler is called outside of Handle, report the route it was registered for
+				route = RouteInfo{Method: method, Pattern: pattern, Key: hID}
+			}
+			route.Meta = r.meta[route.Key]
+
+			r.handleError(ctx, route, err)
+		}
+	}, middleware...)
+
+	if err := r.Add(method, path, hID); err != nil {
+		_ = r.RemoveHandler(hID)
+		return err
+	}
+
+	return nil
+}
+
+// matchedRoute returns the route Handle matched for ctx. It is read from the matched route or the pooled params
+// when Router.SaveMatchedRoute or Router.PooledParams is enabled, otherwise the request is searched again.
+func (r *Router) matchedRoute(ctx *fasthttp.RequestCtx) (RouteInfo, bool) {
+	i := r.methodIndexOf(string(ctx.Method()))
+	if i == -1 {
+		return RouteInfo{}, false
+	}
+
+	route := RouteInfo{Method: methods[i]}
+	if m := MatchedRoute(ctx); m != nil {
+		route.Pattern, route.Key = m.Pattern, m.Key
+		return route, true
+	}
+	if ps := ParamsFromCtx(ctx); ps != nil {
+		route.Pattern, route.Key = ps.Pattern(), ps.Key()
+		return route, true
+	}
+
+	c := capturesPool.Get().(*radix.Captures)
+	defer capturesPool.Put(c)
+
+	m := r.Trees[i].SearchBytes(ctx.Path(), c)
+	if m == nil {
+		return RouteInfo{}, false
+	}
+
+	route.Pattern, route.Key = m.Pattern, m.Key
+	return route, true
+}
+
+func (r *Router) handleError(ctx *fasthttp.RequestCtx, route RouteInfo, err error) {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(ctx, route, err)
+		return
+	}
+
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		r.HandleParamError(ctx, err)
+		return
+	}
+
+	ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+}
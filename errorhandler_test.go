@@ -0,0 +1,134 @@
+package httprouter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/makasim/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouter_RegisterErrorHandler(main *testing.T) {
+	getUser := func(ctx *fasthttp.RequestCtx) error {
+		ps := httprouter.ParamsFromCtx(ctx)
+
+		id, err := ps.Int("id")
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return fmt.Errorf("user %d not found", id)
+		}
+
+		ctx.SetBodyString(ps.Get("id"))
+		return nil
+	}
+
+	main.Run("Default", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+
+		ctx := serve(r, "GET", "/users/1")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.Equal(t, "1", string(ctx.Response.Body()))
+
+		ctx = serve(r, "GET", "/users/abc")
+		require.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		require.Equal(t, `param "id": invalid int "abc": invalid syntax`, string(ctx.Response.Body()))
+
+		ctx = serve(r, "GET", "/users/0")
+		require.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+		require.Equal(t, "Internal Server Error", string(ctx.Response.Body()))
+	})
+
+	main.Run("ErrorHandler", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		r.ColonSyntax = true
+
+		var route httprouter.RouteInfo
+		r.ErrorHandler = func(ctx *fasthttp.RequestCtx, ri httprouter.RouteInfo, err error) {
+			route = ri
+
+			ctx.SetContentType("application/problem+json")
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetBodyString(`{"detail":"` + err.Error() + `"}`)
+		}
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/:id", getUser))
+		r.SetMeta(1, httprouter.Meta{Name: "get-user"})
+
+		ctx := serve(r, "GET", "/users/0")
+		require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+		require.Equal(t, "application/problem+json", string(ctx.Response.Header.ContentType()))
+		require.Equal(t, `{"detail":"user 0 not found"}`, string(ctx.Response.Body()))
+		require.Equal(t, httprouter.RouteInfo{
+			Method:  "GET",
+			Pattern: "/users/{id}",
+			Key:     1,
+			Meta:    httprouter.Meta{Name: "get-user"},
+		}, route)
+	})
+
+	main.Run("Group", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+
+		var route httprouter.RouteInfo
+		r.ErrorHandler = func(ctx *fasthttp.RequestCtx, ri httprouter.RouteInfo, err error) {
+			route = ri
+			ctx.WriteString(err.Error())
+		}
+
+		g := r.Group("/api", writeMiddleware("api:"))
+		require.NoError(t, g.RegisterErrorHandler("GET", "/users/{id}", getUser))
+
+		ctx := serve(r, "GET", "/api/users/0")
+		require.Equal(t, "api:user 0 not found", string(ctx.Response.Body()))
+		require.Equal(t, "/api/users/{id}", route.Pattern)
+	})
+
+	main.Run("MatchedRoute", func(t *testing.T) {
+		for name, setup := range map[string]func(r *httprouter.Router){
+			"UserValues":       func(r *httprouter.Router) {},
+			"PooledParams":     func(r *httprouter.Router) { r.PooledParams = true },
+			"SaveMatchedRoute": func(r *httprouter.Router) { r.SaveMatchedRoute = true },
+		} {
+			r := httprouter.New()
+			setup(r)
+
+			var route httprouter.RouteInfo
+			r.ErrorHandler = func(_ *fasthttp.RequestCtx, ri httprouter.RouteInfo, _ error) {
+				route = ri
+			}
+			require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", func(*fasthttp.RequestCtx) error {
+				return fmt.Errorf("failed")
+			}))
+			r.SetMeta(1, httprouter.Meta{Name: "user"})
+			require.NoError(t, r.Add("POST", "/accounts/{id}", 1))
+
+			serve(r, "POST", "/accounts/1")
+			require.Equal(t, httprouter.RouteInfo{
+				Method:  "POST",
+				Pattern: "/accounts/{id}",
+				Key:     1,
+				Meta:    httprouter.Meta{Name: "user"},
+			}, route, name)
+
+			serve(r, "GET", "/users/1")
+			require.Equal(t, "/users/{id}", route.Pattern, name)
+		}
+	})
+
+	main.Run("Invalid", func(t *testing.T) {
+		r := httprouter.New()
+		require.EqualError(t, r.RegisterErrorHandler("GET", "/users/{id}", nil), "handler is nil")
+
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+		require.Error(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+
+		_, err := r.GetHandler(2)
+		require.Error(t, err)
+	})
+}
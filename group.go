@@ -0,0 +1,115 @@
+package httprouter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Middleware wraps a request handler with additional behavior.
+type Middleware func(fasthttp.RequestHandler) fasthttp.RequestHandler
+
+// Group registers routes under a shared path prefix and wraps their handlers with shared middleware.
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a route group with the given prefix.
+// Middleware is applied in the order given, the first one being the outermost.
+func (r *Router) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     r,
+		prefix:     prefix,
+		middleware: middleware,
+	}
+}
+
+// Group creates a nested group. The prefix is joined to the parent prefix,
+// the parent middleware wraps the nested group middleware.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     joinPath(g.prefix, prefix),
+		middleware: g.chain(middleware),
+	}
+}
+
+// Prefix returns the full path prefix of the group.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// Register wraps handler with the group middleware, stores it under handlerID in the Router.Handlers map
+// and registers a route for method and the prefixed path.
+// It fails if handlerID is taken by a handler added with AddHandler, which would shadow handler.
+//
+// Deprecated: use RegisterHandler, it takes the id from the handler registry.
+func (g *Group) Register(method, path string, handlerID uint64, handler fasthttp.RequestHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+	if _, err := g.router.GetHandler(handlerID); err == nil {
+		return fmt.Errorf("handler id %d is taken by AddHandler", handlerID)
+	}
+
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		handler = g.middleware[i](handler)
+	}
+
+	if err := g.router.Add(method, joinPath(g.prefix, path), handlerID); err != nil {
+		return err
+	}
+
+	g.router.Handlers[handlerID] = handler
+	return nil
+}
+
+// RegisterHandler adds handler wrapped with the group middleware followed by the given one
+// and registers a route for method and the prefixed path.
+func (g *Group) RegisterHandler(method, path string, handler fasthttp.RequestHandler, middleware ...Middleware) error {
+	return g.router.RegisterHandler(method, joinPath(g.prefix, path), handler, g.chain(middleware)...)
+}
+
+// RegisterErrorHandler works like RegisterHandler for a handler that returns an error, see Router.RegisterErrorHandler.
+func (g *Group) RegisterErrorHandler(method, path string, handler ErrorRequestHandler, middleware ...Middleware) error {
+	return g.router.RegisterErrorHandler(method, joinPath(g.prefix, path), handler, g.chain(middleware)...)
+}
+
+// Add adds a route for method and the prefixed path. No handler is stored for handlerID.
+func (g *Group) Add(method, path string, handlerID uint64) error {
+	return g.router.Add(method, joinPath(g.prefix, path), handlerID)
+}
+
+// Remove removes a route for method and the prefixed path.
+func (g *Group) Remove(method, path string) error {
+	return g.router.Remove(method, joinPath(g.prefix, path))
+}
+
+func (g *Group) chain(middleware []Middleware) []Middleware {
+	mw := make([]Middleware, 0, len(g.middleware)+len(middleware))
+	mw = append(mw, g.middleware...)
+	mw = append(mw, middleware...)
+
+	return mw
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+
+	if strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, "/") {
+		return prefix + path[1:]
+	}
+	if !strings.HasSuffix(prefix, "/") && !strings.HasPrefix(path, "/") {
+		return prefix + "/" + path
+	}
+
+	return prefix + path
+}
@@ -0,0 +1,135 @@
+package httprouter_test
+
+import (
+	"testing"
+
+	"github.com/makasim/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestGroup(main *testing.T) {
+	main.Run("Prefix", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.Equal(t, "/api", r.Group("/api").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api").Group("/v1").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api/").Group("/v1").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api").Group("v1").Prefix())
+	})
+
+	main.Run("Register", func(t *testing.T) {
+		r := httprouter.New()
+
+		api := r.Group("/api")
+		v1 := api.Group("/v1")
+
+		require.NoError(t, api.Register("GET", "/status", 1, writeHandler("status")))
+		require.NoError(t, v1.Register("GET", "/users/{id}", 2, writeHandler("user")))
+
+		ctx := serve(r, "GET", "/api/status")
+		require.Equal(t, "status", string(ctx.Response.Body()))
+
+		ctx = serve(r, "GET", "/api/v1/users/123")
+		require.Equal(t, "user", string(ctx.Response.Body()))
+		require.Equal(t, []byte("123"), ctx.UserValue("id"))
+
+		ctx = serve(r, "GET", "/users/123")
+		require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	})
+
+	main.Run("RegisterNil", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.EqualError(t, r.Group("/api").Register("GET", "/status", 1, nil), "handler is nil")
+	})
+
+	main.Run("RegisterTakenID", func(t *testing.T) {
+		r := httprouter.New()
+
+		hID := r.AddHandler(writeHandler("registry"))
+		require.EqualError(t, r.Group("/api").Register("GET", "/status", hID, writeHandler("status")), "handler id 1 is taken by AddHandler")
+		require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/api/status").Response.StatusCode())
+	})
+
+	main.Run("RegisterInvalidMethod", func(t *testing.T) {
+		r := httprouter.New()
+
+		err := r.Group("/api").Register("UNSUPPORTED", "/status", 1, writeHandler("status"))
+		require.EqualError(t, err, "method not allowed")
+		require.Empty(t, r.Handlers)
+	})
+
+	main.Run("RegisterHandler", func(t *testing.T) {
+		r := httprouter.New()
+
+		api := r.Group("/api", writeMiddleware("a"))
+		require.NoError(t, api.RegisterHandler("GET", "/users/{id}", writeHandler("user"), writeMiddleware("b")))
+
+		ctx := serve(r, "GET", "/api/users/123")
+		require.Equal(t, "abuser", string(ctx.Response.Body()))
+		require.Empty(t, r.Handlers)
+
+		require.EqualError(t, api.RegisterHandler("GET", "/status", nil), "handler is nil")
+	})
+
+	main.Run("MiddlewareOrder", func(t *testing.T) {
+		r := httprouter.New()
+
+		api := r.Group("/api", writeMiddleware("a"), writeMiddleware("b"))
+		v1 := api.Group("/v1", writeMiddleware("c"))
+
+		require.NoError(t, api.Register("GET", "/status", 1, writeHandler("h")))
+		require.NoError(t, v1.Register("GET", "/status", 2, writeHandler("h")))
+
+		require.Equal(t, "abh", string(serve(r, "GET", "/api/status").Response.Body()))
+		require.Equal(t, "abch", string(serve(r, "GET", "/api/v1/status").Response.Body()))
+	})
+
+	main.Run("Remove", func(t *testing.T) {
+		r := httprouter.New()
+
+		api := r.Group("/api")
+		require.NoError(t, api.Register("GET", "/foo", 1, writeHandler("foo")))
+		require.NoError(t, api.Register("GET", "/bar", 2, writeHandler("bar")))
+
+		require.NoError(t, api.Remove("GET", "/foo"))
+		require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/api/foo").Response.StatusCode())
+		require.Equal(t, "bar", string(serve(r, "GET", "/api/bar").Response.Body()))
+
+		require.NoError(t, r.Remove("GET", "/api/bar"))
+		require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/api/bar").Response.StatusCode())
+	})
+
+	main.Run("Add", func(t *testing.T) {
+		r := httprouter.New()
+		r.Handlers[1] = writeHandler("foo")
+
+		require.NoError(t, r.Group("/api").Add("GET", "/foo", 1))
+		require.Equal(t, "foo", string(serve(r, "GET", "/api/foo").Response.Body()))
+	})
+}
+
+func writeHandler(body string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.WriteString(body)
+	}
+}
+
+func writeMiddleware(body string) httprouter.Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			ctx.WriteString(body)
+			next(ctx)
+		}
+	}
+}
+
+func serve(r *httprouter.Router, method, path string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.URI().SetPath(path)
+	r.Handle(ctx)
+
+	return ctx
+}
@@ -0,0 +1,202 @@
+package param
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/makasim/httprouter/radix"
+)
+
+// bindField is a struct field tagged with path.
+type bindField struct {
+	index []int
+	field string
+	param string
+	// typ is the field type as reported by Error.
+	typ string
+	set func(v reflect.Value, s string) error
+}
+
+type bindFields struct {
+	fields []bindField
+	err    error
+}
+
+// bindCache maps struct types to their bind fields, so reflection runs once per type.
+var bindCache sync.Map
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Getter looks a param value up by name, an empty value is a missing param.
+type Getter interface {
+	Get(name string) string
+}
+
+// Bind sets fields of the struct dst points to from params, a field is bound to the param named by its path tag.
+// It is generic over the params type, so router params are not boxed and binding does not allocate.
+func Bind[G Getter](ps G, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: want non-nil pointer to struct, got %T", dst)
+	}
+	rv = rv.Elem()
+
+	fields, err := bindFieldsOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range fields {
+		v := ps.Get(f.param)
+		if v == "" {
+			errs = append(errs, Missing(f.param, f.typ))
+			continue
+		}
+
+		if err := f.set(rv.FieldByIndex(f.index), v); err != nil {
+			errs = append(errs, Invalid(f.param, v, f.typ, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckBind reports whether values of v's struct type can be bound from params of pattern:
+// every path tagged field must have a supported type and name a param of pattern.
+func CheckBind(pattern string, v interface{}) error {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("bind: want struct or pointer to struct, got %T", v)
+	}
+
+	fields, err := bindFieldsOf(t)
+	if err != nil {
+		return err
+	}
+
+	p, err := radix.ParsePattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{})
+	for _, name := range p.ParamNames() {
+		names[name] = struct{}{}
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if _, ok := names[f.param]; !ok {
+			errs = append(errs, fmt.Errorf("bind %s: field %s: param %q not in pattern %q", t, f.field, f.param, pattern))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func bindFieldsOf(t reflect.Type) ([]bindField, error) {
+	if cached, ok := bindCache.Load(t); ok {
+		bf := cached.(bindFields)
+		return bf.fields, bf.err
+	}
+
+	var bf bindFields
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("path")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			errs = append(errs, fmt.Errorf("bind %s: field %s: unexported", t, sf.Name))
+			continue
+		}
+
+		set, typ := bindSetter(sf.Type)
+		if set == nil {
+			errs = append(errs, fmt.Errorf("bind %s: field %s: unsupported type %s", t, sf.Name, sf.Type))
+			continue
+		}
+
+		bf.fields = append(bf.fields, bindField{
+			index: sf.Index,
+			field: sf.Name,
+			param: tag,
+			typ:   typ,
+			set:   set,
+		})
+	}
+	bf.err = errors.Join(errs...)
+
+	cached, _ := bindCache.LoadOrStore(t, bf)
+	bf = cached.(bindFields)
+
+	return bf.fields, bf.err
+}
+
+func bindSetter(t reflect.Type) (func(v reflect.Value, s string) error, string) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return func(v reflect.Value, s string) error {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}, t.String()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value, s string) error {
+			v.SetString(s)
+			return nil
+		}, "string"
+	case reflect.Bool:
+		return func(v reflect.Value, s string) error {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+
+			v.SetBool(b)
+			return nil
+		}, "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, s string) error {
+			i, err := strconv.ParseInt(s, 10, t.Bits())
+			if err != nil {
+				return err
+			}
+
+			v.SetInt(i)
+			return nil
+		}, t.Kind().String()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value, s string) error {
+			u, err := strconv.ParseUint(s, 10, t.Bits())
+			if err != nil {
+				return err
+			}
+
+			v.SetUint(u)
+			return nil
+		}, t.Kind().String()
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, s string) error {
+			f, err := strconv.ParseFloat(s, t.Bits())
+			if err != nil {
+				return err
+			}
+
+			v.SetFloat(f)
+			return nil
+		}, t.Kind().String()
+	}
+
+	return nil, ""
+}
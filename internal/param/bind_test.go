@@ -0,0 +1,134 @@
+package param_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/makasim/httprouter/internal/param"
+	"github.com/makasim/httprouter/radix"
+	"github.com/stretchr/testify/require"
+)
+
+type params map[string]string
+
+func (ps params) Get(name string) string {
+	return ps[name]
+}
+
+type bindOrder struct {
+	UserID  int64     `path:"user_id"`
+	OrderID string    `path:"id"`
+	Page    uint8     `path:"page"`
+	Draft   bool      `path:"draft"`
+	Price   float64   `path:"price"`
+	Date    time.Time `path:"date"`
+	Rest    string    `path:"rest"`
+	Body    string
+	Skipped string `path:"-"`
+}
+
+func TestBind(main *testing.T) {
+	main.Run("OK", func(t *testing.T) {
+		var req bindOrder
+		require.NoError(t, param.Bind(params{
+			"user_id": "-42",
+			"id":      "abc",
+			"page":    "7",
+			"draft":   "true",
+			"price":   "9.5",
+			"date":    "2024-02-29T10:00:00Z",
+			"rest":    "a/b",
+			"Body":    "ignored",
+		}, &req))
+
+		require.Equal(t, bindOrder{
+			UserID:  -42,
+			OrderID: "abc",
+			Page:    7,
+			Draft:   true,
+			Price:   9.5,
+			Date:    time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+			Rest:    "a/b",
+		}, req)
+	})
+
+	main.Run("Errors", func(t *testing.T) {
+		req := bindOrder{Body: "kept"}
+		err := param.Bind(params{
+			"user_id": "abc",
+			"id":      "abc",
+			"page":    "300",
+			"draft":   "true",
+			"price":   "9.5",
+			"date":    "2024-02-29T10:00:00Z",
+		}, &req)
+
+		require.EqualError(t, err, `param "user_id": invalid int64 "abc": invalid syntax
+param "page": invalid uint8 "300": value out of range
+param "rest": param missing`)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+		require.ErrorIs(t, err, strconv.ErrRange)
+		require.ErrorIs(t, err, param.ErrMissing)
+
+		var paramErr *param.Error
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, "user_id", paramErr.Name)
+
+		// valid fields are set
+		require.Equal(t, "abc", req.OrderID)
+		require.Equal(t, "kept", req.Body)
+	})
+
+	main.Run("InvalidDst", func(t *testing.T) {
+		var req bindOrder
+		require.EqualError(t, param.Bind(params{}, req), "bind: want non-nil pointer to struct, got param_test.bindOrder")
+		require.EqualError(t, param.Bind(params{}, (*bindOrder)(nil)), "bind: want non-nil pointer to struct, got *param_test.bindOrder")
+
+		s := "str"
+		require.EqualError(t, param.Bind(params{}, &s), "bind: want non-nil pointer to struct, got *string")
+	})
+
+	main.Run("UnsupportedField", func(t *testing.T) {
+		type req struct {
+			IDs  []int  `path:"ids"`
+			name string `path:"name"`
+		}
+
+		err := param.Bind(params{"ids": "1"}, &req{})
+		require.EqualError(t, err, `bind param_test.req: field IDs: unsupported type []int
+bind param_test.req: field name: unexported`)
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		type req struct {
+			UserID int64  `path:"user_id"`
+			ID     string `path:"id"`
+		}
+		ps := params{"user_id": "42", "id": "abc"}
+
+		var in req
+		require.NoError(t, param.Bind(ps, &in))
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			_ = param.Bind(ps, &in)
+		}))
+	})
+}
+
+func TestCheckBind(t *testing.T) {
+	require.NoError(t, param.CheckBind("/users/{user_id}/orders/{id}/{page}/{draft}/{price}/{date}/{*rest}", bindOrder{}))
+	require.NoError(t, param.CheckBind("/users/{user_id}/orders/{id}/{page}/{draft}/{price}/{date}/{*rest}", &bindOrder{}))
+
+	type req struct {
+		UserID  int    `path:"user_id"`
+		OrderID string `path:"id"`
+		Page    int    `path:"page"`
+	}
+	require.EqualError(t, param.CheckBind("/users/{user_id}/orders/{order_id}", &req{}), `bind param_test.req: field OrderID: param "id" not in pattern "/users/{user_id}/orders/{order_id}"
+bind param_test.req: field Page: param "page" not in pattern "/users/{user_id}/orders/{order_id}"`)
+
+	require.EqualError(t, param.CheckBind("/users/{id}", 1), "bind: want struct or pointer to struct, got int")
+
+	var patternErr *radix.PatternError
+	require.ErrorAs(t, param.CheckBind("/users/{id", bindOrder{}), &patternErr)
+}
@@ -0,0 +1,157 @@
+// Package param converts route param values for the typed accessors and Bind of both routers.
+package param
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrMissing = fmt.Errorf("param missing")
+
+// Error is returned by typed accessors when a param is missing or cannot be converted.
+type Error struct {
+	Name  string
+	Value string
+	// Type is the requested type: int, uint64, bool, uuid or time.
+	Type string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if errors.Is(e.Err, ErrMissing) {
+		return fmt.Sprintf("param %q: %v", e.Name, e.Err)
+	}
+
+	return fmt.Sprintf("param %q: invalid %s %q: %v", e.Name, e.Type, e.Value, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Missing returns the error of a param that is not set.
+func Missing(name, typ string) *Error {
+	return &Error{Name: name, Type: typ, Err: ErrMissing}
+}
+
+// Invalid returns the error of a param value that failed to convert to typ.
+func Invalid(name, value, typ string, err error) *Error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err // the value is already in the message
+	}
+
+	return &Error{Name: name, Value: value, Type: typ, Err: err}
+}
+
+// Int parses the value of the named param as a decimal int.
+func Int(name, value string) (int, error) {
+	if value == "" {
+		return 0, Missing(name, "int")
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, Invalid(name, value, "int", err)
+	}
+
+	return i, nil
+}
+
+// Uint64 parses the value of the named param as a decimal uint64.
+func Uint64(name, value string) (uint64, error) {
+	if value == "" {
+		return 0, Missing(name, "uint64")
+	}
+
+	u, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, Invalid(name, value, "uint64", err)
+	}
+
+	return u, nil
+}
+
+// Bool parses the value of the named param with strconv.ParseBool.
+func Bool(name, value string) (bool, error) {
+	if value == "" {
+		return false, Missing(name, "bool")
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, Invalid(name, value, "bool", err)
+	}
+
+	return b, nil
+}
+
+// UUID parses the value of the named param as a UUID in the canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form.
+func UUID(name, value string) ([16]byte, error) {
+	if value == "" {
+		return [16]byte{}, Missing(name, "uuid")
+	}
+
+	u, err := parseUUID(value)
+	if err != nil {
+		return [16]byte{}, Invalid(name, value, "uuid", err)
+	}
+
+	return u, nil
+}
+
+// Time parses the value of the named param with time.Parse and the given layout.
+func Time(name, value, layout string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, Missing(name, "time")
+	}
+
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, Invalid(name, value, "time", err)
+	}
+
+	return t, nil
+}
+
+var errInvalidUUID = fmt.Errorf("invalid uuid format")
+
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, errInvalidUUID
+	}
+
+	j := 0
+	for i := 0; i < len(s); i += 2 {
+		if s[i] == '-' {
+			i++
+		}
+
+		hi, ok1 := fromHex(s[i])
+		lo, ok2 := fromHex(s[i+1])
+		if !ok1 || !ok2 {
+			return [16]byte{}, errInvalidUUID
+		}
+
+		u[j] = hi<<4 | lo
+		j++
+	}
+
+	return u, nil
+}
+
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+
+	return 0, false
+}
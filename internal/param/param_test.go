@@ -0,0 +1,89 @@
+package param_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/makasim/httprouter/internal/param"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInt(t *testing.T) {
+	i, err := param.Int("id", "123")
+	require.NoError(t, err)
+	require.Equal(t, 123, i)
+
+	i, err = param.Int("id", "-5")
+	require.NoError(t, err)
+	require.Equal(t, -5, i)
+
+	_, err = param.Int("id", "abc")
+	require.EqualError(t, err, `param "id": invalid int "abc": invalid syntax`)
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestUint64(t *testing.T) {
+	u, err := param.Uint64("id", "18446744073709551615")
+	require.NoError(t, err)
+	require.Equal(t, uint64(18446744073709551615), u)
+
+	_, err = param.Uint64("id", "-5")
+	require.EqualError(t, err, `param "id": invalid uint64 "-5": invalid syntax`)
+
+	_, err = param.Uint64("id", "18446744073709551616")
+	require.EqualError(t, err, `param "id": invalid uint64 "18446744073709551616": value out of range`)
+	require.ErrorIs(t, err, strconv.ErrRange)
+}
+
+func TestBool(t *testing.T) {
+	b, err := param.Bool("flag", "true")
+	require.NoError(t, err)
+	require.True(t, b)
+
+	_, err = param.Bool("flag", "abc")
+	require.EqualError(t, err, `param "flag": invalid bool "abc": invalid syntax`)
+}
+
+func TestUUID(t *testing.T) {
+	u, err := param.UUID("id", "123E4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+	require.Equal(t, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, u)
+
+	_, err = param.UUID("id", "abc")
+	require.EqualError(t, err, `param "id": invalid uuid "abc": invalid uuid format`)
+
+	_, err = param.UUID("id", "123e4567-e89b-12d3-a456-42661417400g")
+	require.Error(t, err)
+	_, err = param.UUID("id", "123e4567ae89b-12d3-a456-426614174000")
+	require.Error(t, err)
+}
+
+func TestTime(t *testing.T) {
+	tm, err := param.Time("date", "2024-02-29", time.DateOnly)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), tm)
+
+	_, err = param.Time("date", "abc", time.DateOnly)
+	require.ErrorContains(t, err, `param "date": invalid time "abc": `)
+}
+
+func TestMissing(t *testing.T) {
+	_, err := param.Int("id", "")
+	require.EqualError(t, err, `param "id": param missing`)
+	require.ErrorIs(t, err, param.ErrMissing)
+
+	var paramErr *param.Error
+	require.ErrorAs(t, err, &paramErr)
+	require.Equal(t, "id", paramErr.Name)
+	require.Equal(t, "int", paramErr.Type)
+
+	for _, err := range []error{
+		func() error { _, err := param.Uint64("id", ""); return err }(),
+		func() error { _, err := param.Bool("id", ""); return err }(),
+		func() error { _, err := param.UUID("id", ""); return err }(),
+		func() error { _, err := param.Time("id", "", time.DateOnly); return err }(),
+	} {
+		require.ErrorIs(t, err, param.ErrMissing)
+	}
+}
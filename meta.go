@@ -0,0 +1,68 @@
+package httprouter
+
+// Meta describes a handler key and the routes registered for it.
+type Meta struct {
+	Name string
+	Tags []string
+	// Values holds arbitrary metadata such as the owning team, auth scopes or a rate-limit class.
+	Values map[string]interface{}
+}
+
+// Value returns the metadata value stored under key, or nil.
+func (m Meta) Value(key string) interface{} {
+	return m.Values[key]
+}
+
+// HasTag reports whether tag is among the meta tags.
+func (m Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Method  string
+	Pattern string
+	Key     uint64
+	Meta    Meta
+}
+
+// SetMeta attaches meta to the handler key.
+// It is not safe for concurrent use, same as Add.
+func (r *Router) SetMeta(key uint64, meta Meta) {
+	r.meta[key] = meta
+}
+
+// Meta returns metadata attached to the handler key.
+// Use it together with HandlerKeyUserValue to read metadata at request time.
+func (r *Router) Meta(key uint64) (Meta, bool) {
+	meta, ok := r.meta[key]
+	return meta, ok
+}
+
+// RemoveMeta removes metadata attached to the handler key.
+func (r *Router) RemoveMeta(key uint64) {
+	delete(r.meta, key)
+}
+
+// Routes returns all registered routes with their metadata.
+func (r *Router) Routes() []RouteInfo {
+	var routes []RouteInfo
+	for i, tree := range r.Trees {
+		for _, route := range tree.Routes() {
+			routes = append(routes, RouteInfo{
+				Method:  methods[i],
+				Pattern: route.Pattern,
+				Key:     route.Key,
+				Meta:    r.meta[route.Key],
+			})
+		}
+	}
+
+	return routes
+}
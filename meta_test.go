@@ -0,0 +1,56 @@
+package httprouter_test
+
+import (
+	"testing"
+
+	"github.com/makasim/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouter_Meta(main *testing.T) {
+	main.Run("Routes", func(t *testing.T) {
+		r := httprouter.New()
+
+		meta := httprouter.Meta{
+			Name: "get-user",
+			Tags: []string{"users"},
+			Values: map[string]interface{}{
+				"team": "identity",
+			},
+		}
+		r.SetMeta(1, meta)
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 1))
+		require.NoError(t, r.Add("POST", "/users", 2))
+
+		require.Equal(t, []httprouter.RouteInfo{
+			{Method: "GET", Pattern: "/users/{id}", Key: 1, Meta: meta},
+			{Method: "POST", Pattern: "/users", Key: 2},
+		}, r.Routes())
+
+		actMeta, ok := r.Meta(1)
+		require.True(t, ok)
+		require.Equal(t, "identity", actMeta.Value("team"))
+		require.True(t, actMeta.HasTag("users"))
+
+		r.RemoveMeta(1)
+		_, ok = r.Meta(1)
+		require.False(t, ok)
+	})
+
+	main.Run("RequestTime", func(t *testing.T) {
+		r := httprouter.New()
+		r.SetMeta(1, httprouter.Meta{Name: "get-user"})
+
+		var actMeta httprouter.Meta
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			actMeta, _ = r.Meta(ctx.UserValue(httprouter.HandlerKeyUserValue).(uint64))
+		}
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 1))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, "get-user", actMeta.Name)
+	})
+}
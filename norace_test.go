@@ -0,0 +1,5 @@
+//go:build !race
+
+package httprouter_test
+
+const raceEnabled = false
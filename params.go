@@ -0,0 +1,205 @@
+package httprouter
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/makasim/httprouter/internal/param"
+	"github.com/makasim/httprouter/radix"
+	"github.com/savsgio/gotils"
+	"github.com/valyala/fasthttp"
+)
+
+// Param is a route param, Key is the param name as it is set to the request user values.
+type Param struct {
+	Key   string
+	Value string
+}
+
+type Params []Param
+
+// Get returns the value of the first param with the given name, or an empty string.
+func (ps Params) Get(name string) string {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value
+		}
+	}
+
+	return ""
+}
+
+// ParamsUserValue is the user value key under which *RequestParams is stored when Router.PooledParams is enabled.
+const ParamsUserValue = "fasthttprouter.params"
+
+var requestParamsPool = sync.Pool{
+	New: func() interface{} {
+		return &RequestParams{
+			params: make(Params, 0, 8),
+		}
+	},
+}
+
+// RequestParams holds params, handler key and pattern of the matched route.
+// It is taken from a pool and returned to it by Close, which fasthttp calls when the request finishes,
+// so it must not be used after the handler returns.
+type RequestParams struct {
+	params  Params
+	key     uint64
+	pattern string
+}
+
+// ParamsFromCtx returns params stored by Handle, or nil if Router.PooledParams is disabled or no route matched.
+func ParamsFromCtx(ctx *fasthttp.RequestCtx) *RequestParams {
+	ps, _ := ctx.UserValue(ParamsUserValue).(*RequestParams)
+	return ps
+}
+
+// Get returns the value of the first param with the given name, or an empty string.
+func (ps *RequestParams) Get(name string) string {
+	return ps.params.Get(name)
+}
+
+// Params returns params in the pattern order, the slice is reused by later requests.
+func (ps *RequestParams) Params() Params {
+	return ps.params
+}
+
+// Key returns the handler key of the matched route.
+func (ps *RequestParams) Key() uint64 {
+	return ps.key
+}
+
+// Pattern returns the pattern of the matched route.
+func (ps *RequestParams) Pattern() string {
+	return ps.pattern
+}
+
+// Close resets ps and puts it back to the pool. It implements io.Closer for fasthttp to release ps with the request user values.
+func (ps *RequestParams) Close() error {
+	ps.params = ps.params[:0]
+	ps.key = 0
+	ps.pattern = ""
+	requestParamsPool.Put(ps)
+
+	return nil
+}
+
+func (ps *RequestParams) set(route *radix.Route, c *radix.Captures, copyValues bool) {
+	ps.key = route.Key
+	ps.pattern = route.Pattern
+
+	for i := 0; i < c.Len(); i++ {
+		v := c.Value(i)
+		if copyValues {
+			v = strings.Clone(v)
+		}
+
+		ps.params = append(ps.params, Param{Key: c.At(i).Name, Value: v})
+	}
+}
+
+// ParamError is returned by typed Params accessors when a param is missing or cannot be converted.
+// Router.HandleParamError turns it into a 400 response.
+type ParamError = param.Error
+
+var ErrParamMissing = param.ErrMissing
+
+// Int returns the param value parsed as a decimal int.
+func (ps Params) Int(name string) (int, error) {
+	return param.Int(name, ps.Get(name))
+}
+
+// Uint64 returns the param value parsed as a decimal uint64.
+func (ps Params) Uint64(name string) (uint64, error) {
+	return param.Uint64(name, ps.Get(name))
+}
+
+// Bool returns the param value parsed with strconv.ParseBool.
+func (ps Params) Bool(name string) (bool, error) {
+	return param.Bool(name, ps.Get(name))
+}
+
+// UUID returns the param value parsed as a UUID in the canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form.
+func (ps Params) UUID(name string) ([16]byte, error) {
+	return param.UUID(name, ps.Get(name))
+}
+
+// Time returns the param value parsed with time.Parse and the given layout.
+func (ps Params) Time(name, layout string) (time.Time, error) {
+	return param.Time(name, ps.Get(name), layout)
+}
+
+// Int returns the param value parsed as a decimal int.
+func (ps *RequestParams) Int(name string) (int, error) {
+	return ps.params.Int(name)
+}
+
+// Uint64 returns the param value parsed as a decimal uint64.
+func (ps *RequestParams) Uint64(name string) (uint64, error) {
+	return ps.params.Uint64(name)
+}
+
+// Bool returns the param value parsed with strconv.ParseBool.
+func (ps *RequestParams) Bool(name string) (bool, error) {
+	return ps.params.Bool(name)
+}
+
+// UUID returns the param value parsed as a UUID in the canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form.
+func (ps *RequestParams) UUID(name string) ([16]byte, error) {
+	return ps.params.UUID(name)
+}
+
+// Time returns the param value parsed with time.Parse and the given layout.
+func (ps *RequestParams) Time(name, layout string) (time.Time, error) {
+	return ps.params.Time(name, layout)
+}
+
+// ParamInt returns the param of the route matched by Router.Handle parsed as a decimal int.
+// Unlike RequestParams.Int, it also reads params stored as user values when Router.PooledParams is disabled.
+func ParamInt(ctx *fasthttp.RequestCtx, name string) (int, error) {
+	return param.Int(name, paramValue(ctx, name))
+}
+
+// ParamUint64 returns the param of the matched route parsed as a decimal uint64, see ParamInt.
+func ParamUint64(ctx *fasthttp.RequestCtx, name string) (uint64, error) {
+	return param.Uint64(name, paramValue(ctx, name))
+}
+
+// ParamBool returns the param of the matched route parsed with strconv.ParseBool, see ParamInt.
+func ParamBool(ctx *fasthttp.RequestCtx, name string) (bool, error) {
+	return param.Bool(name, paramValue(ctx, name))
+}
+
+// ParamUUID returns the param of the matched route parsed as a UUID in the canonical form, see ParamInt.
+func ParamUUID(ctx *fasthttp.RequestCtx, name string) ([16]byte, error) {
+	return param.UUID(name, paramValue(ctx, name))
+}
+
+// ParamTime returns the param of the matched route parsed with time.Parse and the given layout, see ParamInt.
+func ParamTime(ctx *fasthttp.RequestCtx, name, layout string) (time.Time, error) {
+	return param.Time(name, paramValue(ctx, name), layout)
+}
+
+// paramValue returns the param value from RequestParams or the user value set by Router.Handle.
+// The value may reference the request path, it must not be kept after the handler returns.
+func paramValue(ctx *fasthttp.RequestCtx, name string) string {
+	if ps := ParamsFromCtx(ctx); ps != nil {
+		return ps.Get(name)
+	}
+
+	v, _ := ctx.UserValue(name).([]byte)
+	return gotils.B2S(v)
+}
+
+// HandleParamError responds to a request which params failed to convert.
+// It calls ParamErrorHandler if set, otherwise it replies with 400 Bad Request and the error text.
+func (r *Router) HandleParamError(ctx *fasthttp.RequestCtx, err error) {
+	if r.ParamErrorHandler != nil {
+		r.ParamErrorHandler(ctx, err)
+		return
+	}
+
+	ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+}
@@ -0,0 +1,134 @@
+package httprouter_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/makasim/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParams_Typed(t *testing.T) {
+	// conversions are tested in internal/param, the accessors only look values up
+	ps := httprouter.Params{
+		{Key: "id", Value: "123"},
+		{Key: "big", Value: "18446744073709551615"},
+		{Key: "flag", Value: "false"},
+		{Key: "uuid", Value: "123e4567-e89b-12d3-a456-426614174000"},
+		{Key: "date", Value: "2024-02-29"},
+	}
+
+	i, err := ps.Int("id")
+	require.NoError(t, err)
+	require.Equal(t, 123, i)
+
+	u, err := ps.Uint64("big")
+	require.NoError(t, err)
+	require.Equal(t, uint64(18446744073709551615), u)
+
+	b, err := ps.Bool("flag")
+	require.NoError(t, err)
+	require.False(t, b)
+
+	id, err := ps.UUID("uuid")
+	require.NoError(t, err)
+	require.Equal(t, byte(0x12), id[0])
+
+	tm, err := ps.Time("date", time.DateOnly)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), tm)
+
+	_, err = ps.Int("unknown")
+	require.EqualError(t, err, `param "unknown": param missing`)
+	require.ErrorIs(t, err, httprouter.ErrParamMissing)
+
+	var paramErr *httprouter.ParamError
+	require.ErrorAs(t, err, &paramErr)
+	require.Equal(t, "unknown", paramErr.Name)
+}
+
+func TestParamInt(main *testing.T) {
+	for name, pooled := range map[string]bool{"UserValues": false, "PooledParams": true} {
+		pooled := pooled
+
+		main.Run(name, func(t *testing.T) {
+			r := httprouter.New()
+			r.PooledParams = pooled
+			require.NoError(t, r.RegisterHandler("GET", "/users/{id}/{flag}/{uuid}/{date}", func(ctx *fasthttp.RequestCtx) {
+				id, err := httprouter.ParamInt(ctx, "id")
+				if err != nil {
+					r.HandleParamError(ctx, err)
+					return
+				}
+				u, err := httprouter.ParamUint64(ctx, "id")
+				require.NoError(t, err)
+				require.Equal(t, uint64(id), u)
+
+				flag, err := httprouter.ParamBool(ctx, "flag")
+				require.NoError(t, err)
+				uuid, err := httprouter.ParamUUID(ctx, "uuid")
+				require.NoError(t, err)
+				date, err := httprouter.ParamTime(ctx, "date", time.DateOnly)
+				require.NoError(t, err)
+
+				_, err = httprouter.ParamInt(ctx, "unknown")
+				require.ErrorIs(t, err, httprouter.ErrParamMissing)
+
+				ctx.SetBodyString(fmt.Sprintf("%d %v %x %s", id, flag, uuid[0], date.Format(time.DateOnly)))
+			}))
+
+			ctx := serve(r, "GET", "/users/123/true/123e4567-e89b-12d3-a456-426614174000/2024-02-29")
+			require.Equal(t, "123 true 12 2024-02-29", string(ctx.Response.Body()))
+
+			ctx = serve(r, "GET", "/users/abc/true/123e4567-e89b-12d3-a456-426614174000/2024-02-29")
+			require.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+			require.Equal(t, `param "id": invalid int "abc": invalid syntax`, string(ctx.Response.Body()))
+		})
+	}
+}
+
+func TestRouter_HandleParamError(main *testing.T) {
+	handler := func(r *httprouter.Router) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			id, err := httprouter.ParamsFromCtx(ctx).Int("id")
+			if err != nil {
+				r.HandleParamError(ctx, err)
+				return
+			}
+
+			ctx.SetBodyString(strconv.Itoa(id * 2))
+		}
+	}
+
+	main.Run("Default", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", handler(r)))
+
+		require.Equal(t, "246", string(serve(r, "GET", "/users/123").Response.Body()))
+
+		ctx := serve(r, "GET", "/users/abc")
+		require.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		require.Equal(t, `param "id": invalid int "abc": invalid syntax`, string(ctx.Response.Body()))
+	})
+
+	main.Run("Custom", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		r.ParamErrorHandler = func(ctx *fasthttp.RequestCtx, err error) {
+			var paramErr *httprouter.ParamError
+			require.ErrorAs(t, err, &paramErr)
+
+			ctx.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+			ctx.SetBodyString(paramErr.Name)
+		}
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", handler(r)))
+
+		ctx := serve(r, "GET", "/users/abc")
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, ctx.Response.StatusCode())
+		require.Equal(t, "id", string(ctx.Response.Body()))
+	})
+}
@@ -0,0 +1,123 @@
+package radix
+
+import (
+	"sync"
+
+	"github.com/savsgio/gotils"
+)
+
+var capturesPool = sync.Pool{
+	New: func() interface{} {
+		return NewCaptures(8)
+	},
+}
+
+// Capture is a param captured by Tree.SearchCaptures.
+// Name is the param name as in the pattern, a wildcard name keeps one leading asterisk.
+// Start and End are byte offsets of the value in the searched path, End is exclusive.
+type Capture struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// Captures holds params captured by Tree.SearchCaptures. It is meant to be reused:
+// once its capacity fits the routes, searches do not allocate.
+type Captures struct {
+	path  string
+	bytes []byte
+	list  []Capture
+}
+
+// NewCaptures returns Captures with room for capacity params.
+func NewCaptures(capacity int) *Captures {
+	return &Captures{
+		list: make([]Capture, 0, capacity),
+	}
+}
+
+// Path returns the searched path. After Tree.SearchBytes it references the searched slice.
+func (c *Captures) Path() string {
+	return c.path
+}
+
+// Len returns the number of captured params.
+func (c *Captures) Len() int {
+	return len(c.list)
+}
+
+// At returns the i-th captured param.
+func (c *Captures) At(i int) Capture {
+	return c.list[i]
+}
+
+// Value returns the value of the i-th captured param, it references the searched path.
+// After Tree.SearchBytes it is only valid while the searched slice is not modified.
+func (c *Captures) Value(i int) string {
+	return c.path[c.list[i].Start:c.list[i].End]
+}
+
+// ValueBytes returns the value of the i-th captured param.
+// After Tree.SearchBytes it references the searched slice, otherwise it is a copy.
+func (c *Captures) ValueBytes(i int) []byte {
+	if c.bytes == nil {
+		return []byte(c.Value(i))
+	}
+
+	return c.bytes[c.list[i].Start:c.list[i].End:c.list[i].End]
+}
+
+// AppendValue appends the value of the i-th captured param to dst and returns the extended slice.
+// Unlike Value and ValueBytes, the result does not reference the searched path.
+func (c *Captures) AppendValue(dst []byte, i int) []byte {
+	return append(dst, c.path[c.list[i].Start:c.list[i].End]...)
+}
+
+// Get returns the value of the first param with the given name.
+func (c *Captures) Get(name string) (string, bool) {
+	for i := range c.list {
+		if c.list[i].Name == name {
+			return c.Value(i), true
+		}
+	}
+
+	return "", false
+}
+
+func (c *Captures) reset(path string) {
+	c.path = path
+	c.bytes = nil
+	c.list = c.list[:0]
+}
+
+func (c *Captures) resetBytes(path []byte) {
+	c.path = gotils.B2S(path)
+	c.bytes = path
+	c.list = c.list[:0]
+}
+
+// capture adds the param if m matched, value is the first n bytes of rest which is a suffix of the searched path.
+func (c *Captures) capture(m *Node, name, rest string, n int) *Node {
+	if m != nil {
+		start := len(c.path) - len(rest)
+		c.list = append(c.list, Capture{Name: name, Start: start, End: start + n})
+	}
+
+	return m
+}
+
+// captureSlash adds the catch-all param if m matched, the value is rest with the slash preceding it.
+func (c *Captures) captureSlash(m *Node, name, rest string) *Node {
+	if m != nil {
+		end := len(c.path)
+		c.list = append(c.list, Capture{Name: name, Start: end - len(rest) - 1, End: end})
+	}
+
+	return m
+}
+
+func (c *Captures) reverse() {
+	for i, j := 0, len(c.list)-1; i < j; i, j = i+1, j-1 {
+		c.list[i], c.list[j] = c.list[j], c.list[i]
+	}
+}
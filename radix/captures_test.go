@@ -0,0 +1,244 @@
+package radix_test
+
+import (
+	"testing"
+
+	"github.com/makasim/httprouter/radix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeSearchCaptures(main *testing.T) {
+	tree := radix.NewTree()
+	for i, pattern := range []string{
+		"/users/{id}",
+		"/users/{id}/orders/{order}",
+		"/users/{id}/files/{*path}",
+		"/users/admin",
+		"/static/{*}",
+		"/{lang}/about",
+		"/assets/{**path}",
+		"/users/{id}/docs/{**path}",
+	} {
+		var err error
+		tree, err = tree.Insert(pattern, uint64(i+1))
+		require.NoError(main, err)
+	}
+
+	type test struct {
+		path       string
+		expPattern string
+		exp        []radix.Capture
+		expValues  []string
+	}
+
+	tests := map[string]test{
+		"Param": {
+			path:       "/users/123",
+			expPattern: "/users/{id}",
+			exp:        []radix.Capture{{Name: "id", Start: 7, End: 10}},
+			expValues:  []string{"123"},
+		},
+		"TwoParams": {
+			path:       "/users/123/orders/45",
+			expPattern: "/users/{id}/orders/{order}",
+			exp: []radix.Capture{
+				{Name: "id", Start: 7, End: 10},
+				{Name: "order", Start: 18, End: 20},
+			},
+			expValues: []string{"123", "45"},
+		},
+		"Wildcard": {
+			path:       "/users/123/files/a/b.txt",
+			expPattern: "/users/{id}/files/{*path}",
+			exp: []radix.Capture{
+				{Name: "id", Start: 7, End: 10},
+				{Name: "*path", Start: 17, End: 24},
+			},
+			expValues: []string{"123", "a/b.txt"},
+		},
+		"AnonymousWildcard": {
+			path:       "/static/js/app.js",
+			expPattern: "/static/{*}",
+			exp:        []radix.Capture{{Name: "*", Start: 8, End: 17}},
+			expValues:  []string{"js/app.js"},
+		},
+		"CatchAll": {
+			path:       "/assets/js/app.js",
+			expPattern: "/assets/{**path}",
+			exp:        []radix.Capture{{Name: "*path", Start: 7, End: 17}},
+			expValues:  []string{"/js/app.js"},
+		},
+		"CatchAllEmpty": {
+			path:       "/assets/",
+			expPattern: "/assets/{**path}",
+			exp:        []radix.Capture{{Name: "*path", Start: 7, End: 8}},
+			expValues:  []string{"/"},
+		},
+		"CatchAllNoSlash": {
+			path: "/assets",
+		},
+		"CatchAllAfterParam": {
+			path:       "/users/123/docs/",
+			expPattern: "/users/{id}/docs/{**path}",
+			exp: []radix.Capture{
+				{Name: "id", Start: 7, End: 10},
+				{Name: "*path", Start: 15, End: 16},
+			},
+			expValues: []string{"123", "/"},
+		},
+		"Static": {
+			path:       "/users/admin",
+			expPattern: "/users/admin",
+		},
+		"Fallback": {
+			path:       "/en/about",
+			expPattern: "/{lang}/about",
+			exp:        []radix.Capture{{Name: "lang", Start: 1, End: 3}},
+			expValues:  []string{"en"},
+		},
+		"NotFound": {
+			path: "/users/123/unknown",
+		},
+		"Empty": {
+			path: "",
+		},
+	}
+
+	c := radix.NewCaptures(0)
+	for name, tt := range tests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			// the captures are reused, leftovers of the previous search must not leak
+			_ = tree.SearchCaptures("/users/1/orders/2", c)
+
+			route := tree.SearchCaptures(tt.path, c)
+			if tt.expPattern == "" {
+				require.Nil(t, route)
+				require.Equal(t, 0, c.Len())
+				return
+			}
+
+			require.NotNil(t, route)
+			require.Equal(t, tt.expPattern, route.Pattern)
+			require.Equal(t, tt.path, c.Path())
+			require.Equal(t, len(tt.exp), c.Len())
+
+			for i := range tt.exp {
+				require.Equal(t, tt.exp[i], c.At(i))
+				require.Equal(t, tt.expValues[i], c.Value(i))
+
+				v, ok := c.Get(tt.exp[i].Name)
+				require.True(t, ok)
+				require.Equal(t, tt.expValues[i], v)
+			}
+
+			_, ok := c.Get("unknown")
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestTreeSearchCatchAllRoot(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/{**path}", 1)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/users/{id}", 2)
+	require.NoError(t, err)
+
+	c := radix.NewCaptures(0)
+	for path, exp := range map[string]string{
+		"/":          "/",
+		"/index.htm": "/index.htm",
+		"/users/":    "/users/",
+		"/a//b":      "/a//b",
+	} {
+		route := tree.SearchCaptures(path, c)
+		require.NotNil(t, route, path)
+		require.Equal(t, "/{**path}", route.Pattern)
+		require.Equal(t, exp, c.Value(0))
+	}
+
+	route := tree.SearchCaptures("/users/1", c)
+	require.NotNil(t, route)
+	require.Equal(t, "/users/{id}", route.Pattern)
+}
+
+func TestTreeSearchBytes(main *testing.T) {
+	tree, err := radix.NewTree().Insert("/users/{id}/files/{*path}", 1)
+	require.NoError(main, err)
+
+	main.Run("Found", func(t *testing.T) {
+		path := []byte("/users/123/files/a/b.txt")
+		c := radix.NewCaptures(2)
+
+		route := tree.SearchBytes(path, c)
+		require.NotNil(t, route)
+		require.Equal(t, "/users/{id}/files/{*path}", route.Pattern)
+		require.Equal(t, 2, c.Len())
+		require.Equal(t, "123", c.Value(0))
+		require.Equal(t, []byte("123"), c.ValueBytes(0))
+		require.Equal(t, []byte("a/b.txt"), c.ValueBytes(1))
+
+		kept := c.AppendValue(nil, 0)
+		shared := c.ValueBytes(0)
+
+		copy(path, "/users/456")
+		require.Equal(t, []byte("123"), kept)
+		require.Equal(t, []byte("456"), shared)
+	})
+
+	main.Run("NotFound", func(t *testing.T) {
+		c := radix.NewCaptures(2)
+		require.Nil(t, tree.SearchBytes([]byte("/users/123"), c))
+		require.Equal(t, 0, c.Len())
+		require.Nil(t, tree.SearchBytes(nil, c))
+	})
+
+	main.Run("ValueBytesCopy", func(t *testing.T) {
+		c := radix.NewCaptures(2)
+		require.NotNil(t, tree.SearchCaptures("/users/123/files/a", c))
+
+		v := c.ValueBytes(0)
+		require.Equal(t, []byte("123"), v)
+		v[0] = '9'
+		require.Equal(t, "123", c.Value(0))
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		path := []byte("/users/123/files/a/b.txt")
+		c := radix.NewCaptures(2)
+
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			if tree.SearchBytes(path, c) == nil {
+				t.Fatal("route not found")
+			}
+			_ = c.ValueBytes(1)
+		}))
+	})
+}
+
+func TestTreeSearchCapturesNoAllocs(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/{a}/{b}/{c}/{d}/{e}/{*f}", 1)
+	require.NoError(t, err)
+
+	c := radix.NewCaptures(6)
+	allocs := testing.AllocsPerRun(100, func() {
+		if tree.SearchCaptures("/1/2/3/4/5/6/7", c) == nil {
+			t.Fatal("route not found")
+		}
+	})
+	require.Equal(t, float64(0), allocs)
+	require.Equal(t, 6, c.Len())
+	require.Equal(t, "6/7", c.Value(5))
+}
+
+func TestTreeSearchParamsOrder(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/{a}/{b}/{*c}", 1)
+	require.NoError(t, err)
+
+	var names []string
+	tree.Search("/1/2/3", func(n string, v interface{}) {
+		names = append(names, n)
+	})
+	require.Equal(t, []string{"a", "b", "*c"}, names)
+}
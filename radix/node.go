@@ -10,6 +10,67 @@ import (
 
 var ErrPathAlreadyTaken = fmt.Errorf("path already taken")
 var ErrParamNameConflict = fmt.Errorf("param name conflict")
+var ErrPathNotFound = fmt.Errorf("path not found")
+
+// ConflictError is returned by Tree.Insert when a pattern clashes with a registered one.
+// It wraps ErrPathAlreadyTaken or ErrParamNameConflict.
+type ConflictError struct {
+	Err     error
+	Pattern string
+	// Existing is the registered pattern the new one clashes with, and Key is its key.
+	// Existing is empty if the clashing node has no registered route.
+	Existing string
+	Key      uint64
+	// Column is the 1-based byte column in Pattern where the clashing node starts.
+	Column int
+}
+
+func (e *ConflictError) Error() string {
+	if e.Existing == "" {
+		return fmt.Sprintf("%q at column %d: %v", e.Pattern, e.Column, e.Err)
+	}
+
+	return fmt.Sprintf("%q conflicts with %q (key %d) at column %d: %v", e.Pattern, e.Existing, e.Key, e.Column, e.Err)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// conflict is a node level conflict error, Tree.Insert turns it into ConflictError.
+type conflict struct {
+	err error
+	// rest is the part of the inserted path starting at the clashing node.
+	rest  string
+	key   uint64
+	route uint32
+}
+
+func (c conflict) Error() string {
+	return c.err.Error()
+}
+
+func (c conflict) Unwrap() error {
+	return c.err
+}
+
+func pathTaken(n Node, rest string) conflict {
+	return conflict{err: ErrPathAlreadyTaken, rest: rest, key: n.key, route: n.route}
+}
+
+func paramConflict(pn Node, rest string) conflict {
+	c := conflict{err: ErrParamNameConflict, rest: rest, key: pn.key, route: pn.route}
+	if c.route == 0 {
+		pn.walkRoutes(func(n *Node) {
+			if c.route == 0 {
+				c.key = n.key
+				c.route = n.route
+			}
+		})
+	}
+
+	return c
+}
 
 type kind uint8
 
@@ -23,21 +84,39 @@ type Node struct {
 	children []Node
 	key      uint64
 	kind     kind
+	// route is an index of the node route in the tree routes table starting from 1, 0 means no route.
+	route uint32
 }
 
+// Insert returns a copy of the node with the path added.
+// It panics if the path is invalid or conflicts with a registered one, Tree.Insert returns an error instead.
+// A conflict panics with ErrPathAlreadyTaken or ErrParamNameConflict itself.
 func (n Node) Insert(path string, key uint64) Node {
+	n, err := n.insert(path, key, false)
+	if c, ok := err.(conflict); ok {
+		panic(c.err)
+	} else if err != nil {
+		panic(err)
+	}
+
+	return n
+}
+
+// insert returns a copy of the node with the path added, in upsert mode the key of a registered path is replaced.
+// Children slices shared with n are never written to, so n stays intact whether insert succeeds or fails.
+func (n Node) insert(path string, key uint64, upsert bool) (Node, error) {
 	if path == "" {
-		panic("insert: path empty")
+		return Node{}, fmt.Errorf("insert: path empty")
 	}
 	if key < 1 {
-		panic("insert: key empty")
+		return Node{}, fmt.Errorf("insert: key empty")
 	}
 
 	if n.path == "" {
 		if paramStart := findParamStart(path); paramStart != -1 {
 			paramEnd := findParamEnd(path[paramStart:])
 			if paramEnd == -1 {
-				panic(fmt.Sprintf("no right bracket: %v", path))
+				return Node{}, fmt.Errorf("no right bracket: %v", path)
 			}
 
 			n.path = path[:paramStart]
@@ -50,31 +129,33 @@ func (n Node) Insert(path string, key uint64) Node {
 
 			path = path[paramEnd:]
 			if path != "" {
-				child = child.Insert(path, key)
+				var err error
+				if child, err = child.insert(path, key, upsert); err != nil {
+					return Node{}, err
+				}
 			} else {
 				child.key = key
 			}
 
-			n = n.setParamNode(child)
-			return n
+			return n.setParamNode(child), nil
 		}
 
 		if n.key > 0 && n.key != key {
-			panic(ErrPathAlreadyTaken)
+			return Node{}, pathTaken(n, path)
 		}
 
 		n.path = path
 		n.key = key
-		return n
+		return n, nil
 	}
 
 	if n.path == path {
-		if n.key > 0 && n.key != key {
-			panic(ErrPathAlreadyTaken)
+		if !upsert && n.key > 0 && n.key != key {
+			return Node{}, pathTaken(n, path)
 		}
 
 		n.key = key
-		return n
+		return n, nil
 	}
 
 	i := longestCommonPrefix(path, n.path)
@@ -86,13 +167,17 @@ func (n Node) Insert(path string, key uint64) Node {
 		path = path[i:]
 		if path == "" {
 			n.key = key
-			return n
+			return n, nil
 		}
 
 		if findParamStart(path) == 0 {
 			pn, _ := n.paramNode()
-			n = n.setParamNode(updateParamNode(pn, path, key))
-			return n
+			pn, err := updateParamNode(pn, path, key, upsert)
+			if err != nil {
+				return Node{}, err
+			}
+
+			return n.setParamNode(pn), nil
 		}
 	}
 
@@ -103,50 +188,55 @@ func (n Node) Insert(path string, key uint64) Node {
 		}
 
 		if len(child.path) > prefix {
-			child = child.split(prefix)
-			child = child.Insert(path, key)
-			n.children[i] = child
-			return n
+			child, err := child.split(prefix).insert(path, key, upsert)
+			if err != nil {
+				return Node{}, err
+			}
+
+			return n.replaceChild(i, child), nil
 		}
 
 		if len(path) > prefix {
-			n.children[i] = child.Insert(path, key)
-			return n
+			child, err := child.insert(path, key, upsert)
+			if err != nil {
+				return Node{}, err
+			}
+
+			return n.replaceChild(i, child), nil
 		}
 
-		if child.key > 0 && child.key != key {
-			panic(ErrPathAlreadyTaken)
+		if !upsert && child.key > 0 && child.key != key {
+			return Node{}, pathTaken(child, path)
 		}
 
 		child.key = key
-		n.children[i] = child
-		return n
+		return n.replaceChild(i, child), nil
 	}
 
 	if start := findParamStart(path); start >= 0 {
-		if start == 0 {
-			n = n.setParamNode(updateParamNode(Node{kind: param}, path, key))
-			return n
+		pn, err := updateParamNode(Node{kind: param}, path[start:], key, upsert)
+		if err != nil {
+			return Node{}, err
 		}
 
-		n.children = append(n.children, Node{
-			path: path[:start],
-			children: []Node{
-				updateParamNode(Node{kind: param}, path[start:], key),
-			},
-		})
+		if start == 0 {
+			return n.setParamNode(pn), nil
+		}
 
-		return n
+		return n.appendChild(Node{
+			path:     path[:start],
+			children: []Node{pn},
+		}), nil
 	}
 
-	n.children = append(n.children, Node{
+	return n.appendChild(Node{
 		path:     path,
 		key:      key,
 		children: nil,
-	})
-	return n
+	}), nil
 }
 
+// Delete returns a copy of the node with the path removed, n itself is not modified.
 func (n Node) Delete(path string) Node {
 	removeChild := -1
 
@@ -156,44 +246,70 @@ loop:
 		case len(path) < len(child.path):
 			continue
 		case path == child.path && len(child.children) > 0:
-			n.children[i].key = 0
+			child.key = 0
+			child.route = 0
+			n = n.replaceChild(i, child)
 			break loop
 		case path == child.path && len(child.children) == 0:
 			removeChild = i
 			break loop
 		case path[:len(child.path)] == child.path:
-			n.children[i] = child.Delete(path[len(child.path):])
+			n = n.replaceChild(i, child.Delete(path[len(child.path):]))
 			break loop
 		}
 	}
 
 	if removeChild >= 0 {
-		copy(n.children[removeChild:], n.children[removeChild+1:])
-		n.children[len(n.children)-1] = Node{}
-		n.children = n.children[:len(n.children)-1]
+		n = n.removeChild(removeChild)
 	}
 
-	if n.key == 0 && len(n.children) == 1 && n.children[0].kind != param {
-		n.path += n.children[0].path
-		n.key = n.children[0].key
+	// a param node path is its name, merging a static child into it would change what the param matches
+	if n.key == 0 && len(n.children) == 1 && n.kind != param && n.children[0].kind != param {
+		child := n.children[0]
 
-		children := n.children[0].children
-		n.children[0] = Node{}
-		n.children = n.children[:0]
-		n.children = children
+		n.path += child.path
+		n.key = child.key
+		n.route = child.route
+		n.children = child.children
 	}
 
 	return n
 }
 
 func (n *Node) Search(path string, kv func(n string, v interface{})) uint64 {
-	var n1 *Node
+	if m := n.searchKV(path, kv); m != nil {
+		return m.key
+	}
+
+	return 0
+}
+
+// searchKV searches with pooled captures and reports params of the matched route to kv.
+func (n *Node) searchKV(path string, kv func(n string, v interface{})) *Node {
+	c := capturesPool.Get().(*Captures)
+	c.reset(path)
 
+	m := n.search(path, c)
+	if m != nil {
+		for i := len(c.list) - 1; i >= 0; i-- {
+			kv(c.list[i].Name, gotils.S2B(c.Value(i)))
+		}
+	}
+
+	c.reset("")
+	capturesPool.Put(c)
+
+	return m
+}
+
+// search returns the node matching path, or nil if there is no node with a key for the path.
+// Params of the matched route are added to c innermost first, nothing is added if there is no match.
+func (n *Node) search(path string, c *Captures) *Node {
 	switch n.kind {
 	case static:
 		if len(path) > len(n.path) {
 			if len(n.children) == 0 || n.path != path[:len(n.path)] {
-				return 0
+				return nil
 			}
 
 			i := 0
@@ -206,89 +322,136 @@ func (n *Node) Search(path string, kv func(n string, v interface{})) uint64 {
 
 			path = path[len(n.path):]
 			for ; i < l; i++ {
-				n1 = &n.children[i]
+				n1 := &n.children[i]
 				if path[0] == n1.path[0] {
-					if key := n1.Search(path, kv); key > 0 {
-						return key
+					if m := n1.search(path, c); m != nil {
+						return m
 					}
 					break
 				}
 			}
 
 			if hasChildParam {
-				return n.children[0].Search(path, kv)
+				return n.children[0].search(path, c)
 			}
 
-			return 0
+			return nil
 		} else if n.path == path {
-			return n.key
+			if m := n.matched(); m != nil {
+				return m
+			}
+
+			// a {**name} catch-all child also matches the empty rest
+			if len(n.children) > 0 && n.children[0].kind == param {
+				return n.children[0].search("", c)
+			}
 		}
 
-		return 0
+		return nil
 	case param:
+		pn := n.paramName()
+		if len(pn) > 1 && pn[1] == '*' {
+			return c.captureSlash(n.matched(), pn[1:], path)
+		}
+
 		i := findSlashOrEnd(path)
-		switch {
-		case i > 0:
-			pn := n.paramName()
-			originI := i
-			originPath := path
-			path = path[i:]
-
-			if len(path) == 0 {
-				kv(pn, gotils.S2B(originPath[:originI]))
-				return n.key
-			}
+		if i == 0 {
+			return nil
+		}
 
-			if len(n.children) == 0 {
-				// wildcard
-				if pn[0] == '*' {
-					kv(pn, gotils.S2B(originPath))
-					return n.key
-				}
+		rest := path[i:]
 
-				kv(pn, gotils.S2B(originPath[:originI]))
-				return 0
-			}
+		if len(rest) == 0 {
+			return c.capture(n.matched(), pn, path, i)
+		}
 
-			i := 0
-			l := len(n.children)
-			hasChildParam := false
-			if n.children[0].kind == param {
-				i = 1
-				hasChildParam = true
+		if len(n.children) == 0 {
+			// wildcard
+			if pn[0] == '*' {
+				return c.capture(n.matched(), pn, path, len(path))
 			}
 
-			for ; i < l; i++ {
-				n1 := &n.children[i]
-				if path[0] == n1.path[0] {
-					if key := n1.Search(path, kv); key > 0 {
-						kv(pn, gotils.S2B(originPath[:originI]))
-						return key
-					}
-					break
-				}
-			}
+			return nil
+		}
 
-			if hasChildParam {
-				kv(pn, gotils.S2B(originPath[:originI]))
-				return n.children[0].Search(path, kv)
-			}
+		j := 0
+		l := len(n.children)
+		hasChildParam := false
+		if n.children[0].kind == param {
+			j = 1
+			hasChildParam = true
+		}
 
-			// wildcard
-			if pn[0] == '*' {
-				kv(pn, gotils.S2B(originPath))
-				return n.key
+		for ; j < l; j++ {
+			n1 := &n.children[j]
+			if rest[0] == n1.path[0] {
+				if m := n1.search(rest, c); m != nil {
+					return c.capture(m, pn, path, i)
+				}
+				break
 			}
+		}
+
+		if hasChildParam {
+			return c.capture(n.children[0].search(rest, c), pn, path, i)
+		}
 
-			return 0
-		default:
-			return 0
+		// wildcard
+		if pn[0] == '*' {
+			return c.capture(n.matched(), pn, path, len(path))
 		}
+
+		return nil
 	default:
-		return 0
+		return nil
 	}
 }
 
+// find returns the node registered for the pattern path, or nil if there is none.
+func (n *Node) find(path string) *Node {
+	if len(path) < len(n.path) || path[:len(n.path)] != n.path {
+		return nil
+	}
+
+	path = path[len(n.path):]
+	if path == "" {
+		return n
+	}
+
+	for i := range n.children {
+		if m := n.children[i].find(path); m != nil {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) walkRoutes(fn func(n *Node)) {
+	if n.route > 0 {
+		fn(n)
+	}
+
+	for i := range n.children {
+		n.children[i].walkRoutes(fn)
+	}
+}
+
+func (n *Node) cloneRoutes(src []Route, dst *[]Route) {
+	n.walkRoutes(func(n *Node) {
+		*dst = append(*dst, src[n.route-1])
+		n.route = uint32(len(*dst))
+	})
+}
+
+func (n *Node) matched() *Node {
+	if n.key == 0 {
+		return nil
+	}
+
+	return n
+}
+
 func (n Node) paramName() string {
 	if n.kind != param {
 		return ""
@@ -311,17 +474,39 @@ func (n Node) paramNode() (Node, bool) {
 }
 
 func (n Node) setParamNode(pn Node) Node {
-	if len(n.children) == 0 {
-		n.children = append(n.children, pn)
-		return n
+	if len(n.children) > 0 && n.children[0].kind == param {
+		return n.replaceChild(0, pn)
 	}
 
-	if n.children[0].kind != param {
-		n.children = append([]Node{pn}, n.children...)
-		return n
-	}
+	children := make([]Node, 0, len(n.children)+1)
+	children = append(children, pn)
+	n.children = append(children, n.children...)
+	return n
+}
+
+// replaceChild, appendChild and removeChild copy the children, so slices shared with other trees are never written to.
+func (n Node) replaceChild(i int, child Node) Node {
+	children := make([]Node, len(n.children))
+	copy(children, n.children)
+	children[i] = child
+
+	n.children = children
+	return n
+}
+
+func (n Node) appendChild(child Node) Node {
+	children := make([]Node, len(n.children), len(n.children)+1)
+	copy(children, n.children)
+
+	n.children = append(children, child)
+	return n
+}
+
+func (n Node) removeChild(i int) Node {
+	children := make([]Node, 0, len(n.children)-1)
+	children = append(children, n.children[:i]...)
 
-	n.children[0] = pn
+	n.children = append(children, n.children[i+1:]...)
 	return n
 }
 
@@ -599,7 +599,7 @@ func TestNode_InsertInvalid(main *testing.T) {
 	n := Node{}
 
 	main.Run("EmptyPath", func(t *testing.T) {
-		require.PanicsWithValue(t, "insert: path empty", func() {
+		require.PanicsWithError(t, "insert: path empty", func() {
 			n = n.Insert("", 2)
 		})
 	})
@@ -607,7 +607,7 @@ func TestNode_InsertInvalid(main *testing.T) {
 	main.Run("EmptyKey", func(t *testing.T) {
 		n := Node{}
 
-		require.PanicsWithValue(t, "insert: key empty", func() {
+		require.PanicsWithError(t, "insert: key empty", func() {
 			n = n.Insert("/path", 0)
 		})
 	})
@@ -615,7 +615,7 @@ func TestNode_InsertInvalid(main *testing.T) {
 	main.Run("NoParamEnd1", func(t *testing.T) {
 		n := Node{}
 
-		require.PanicsWithValue(t, "no right bracket: /{param", func() {
+		require.PanicsWithError(t, "no right bracket: /{param", func() {
 			n = n.Insert("/{param", 2)
 		})
 	})
@@ -623,7 +623,7 @@ func TestNode_InsertInvalid(main *testing.T) {
 	main.Run("NoParamEnd2", func(t *testing.T) {
 		n := Node{path: "/foo/bar", key: 1}
 
-		require.PanicsWithValue(t, "no right bracket: {param", func() {
+		require.PanicsWithError(t, "no right bracket: {param", func() {
 			n = n.Insert("/foo/{param", 2)
 		})
 	})
@@ -727,5 +727,16 @@ func TestNode_InsertConflict(main *testing.T) {
 				tt.node.Insert(tt.insertPath, tt.insertKey)
 			})
 		})
+
+		main.Run(name+"/ErrorsIs", func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok)
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Equal(t, tt.expectedErr, err)
+			}()
+
+			tt.node.Insert(tt.insertPath, tt.insertKey)
+		})
 	}
 }
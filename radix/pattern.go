@@ -0,0 +1,169 @@
+package radix
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SegmentKind uint8
+
+const (
+	// SegmentStatic is a static part of a pattern, it may span several path segments.
+	SegmentStatic SegmentKind = iota
+	// SegmentParam is a {name} param, it matches up to the next slash.
+	SegmentParam
+	// SegmentWildcard is a {*name} or {**name} param, it matches the rest of the path.
+	SegmentWildcard
+)
+
+func (k SegmentKind) String() string {
+	switch k {
+	case SegmentStatic:
+		return "static"
+	case SegmentParam:
+		return "param"
+	case SegmentWildcard:
+		return "wildcard"
+	default:
+		return "unknown"
+	}
+}
+
+// Segment is a part of a parsed pattern.
+type Segment struct {
+	Kind SegmentKind
+	// Value is the static text, or the param name without braces and asterisk.
+	Value string
+	// Start and End are byte offsets of the segment in the pattern, End is exclusive.
+	Start int
+	End   int
+}
+
+// Pattern is a parsed route pattern.
+type Pattern struct {
+	Raw      string
+	Segments []Segment
+}
+
+func (p Pattern) String() string {
+	return p.Raw
+}
+
+// ParamNames returns names of params and wildcards in the pattern order.
+func (p Pattern) ParamNames() []string {
+	var names []string
+	for _, s := range p.Segments {
+		if s.Kind != SegmentStatic {
+			names = append(names, s.Value)
+		}
+	}
+
+	return names
+}
+
+// HasWildcard reports whether the pattern ends with a wildcard.
+func (p Pattern) HasWildcard() bool {
+	return len(p.Segments) > 0 && p.Segments[len(p.Segments)-1].Kind == SegmentWildcard
+}
+
+// PatternError describes an invalid pattern.
+type PatternError struct {
+	Pattern string
+	// Column is the 1-based byte column of the offending character.
+	Column int
+	Reason string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("pattern %q: %s at column %d", e.Pattern, e.Reason, e.Column)
+}
+
+// ParsePattern parses and validates a route pattern.
+//
+// A pattern starts with a slash. {name} matches a non-empty value up to the next slash and must end a path segment.
+// {*name} matches the rest of the path and must end the pattern, {*} is an anonymous wildcard.
+// {**name} is a catch-all that must follow a slash: its value includes that slash and it also matches an empty rest,
+// so /static/{**path} matches /static/ with "/" and /static/js/app.js with "/js/app.js".
+// Static text may precede a param within a segment, like /rpc.{*method}.
+func ParsePattern(pattern string) (Pattern, error) {
+	if pattern == "" {
+		return Pattern{}, &PatternError{Pattern: pattern, Column: 1, Reason: "empty pattern"}
+	}
+	if pattern[0] != '/' {
+		return Pattern{}, &PatternError{Pattern: pattern, Column: 1, Reason: "pattern must start with /"}
+	}
+
+	p := Pattern{Raw: pattern}
+	staticStart := 0
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '}':
+			return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "unexpected }"}
+		case '{':
+		default:
+			i++
+			continue
+		}
+
+		if staticStart < i {
+			p.Segments = append(p.Segments, Segment{
+				Kind:  SegmentStatic,
+				Value: pattern[staticStart:i],
+				Start: staticStart,
+				End:   i,
+			})
+		}
+
+		end := strings.IndexAny(pattern[i+1:], "{}/")
+		if end == -1 || pattern[i+1+end] != '}' {
+			return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "unclosed {"}
+		}
+		end += i + 1
+
+		seg := Segment{
+			Kind:  SegmentParam,
+			Value: pattern[i+1 : end],
+			Start: i,
+			End:   end + 1,
+		}
+		if strings.HasPrefix(seg.Value, "*") {
+			seg.Kind = SegmentWildcard
+			seg.Value = seg.Value[1:]
+
+			if strings.HasPrefix(seg.Value, "*") {
+				seg.Value = seg.Value[1:]
+				if pattern[i-1] != '/' {
+					return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "catch-all must follow a slash"}
+				}
+			}
+
+			if end+1 != len(pattern) {
+				return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "wildcard must end the pattern"}
+			}
+		} else if seg.Value == "" {
+			return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "empty param name"}
+		}
+
+		if strings.Contains(seg.Value, "*") {
+			return Pattern{}, &PatternError{Pattern: pattern, Column: i + 1, Reason: "unexpected * in param name"}
+		}
+		if end+1 < len(pattern) && pattern[end+1] != '/' {
+			return Pattern{}, &PatternError{Pattern: pattern, Column: end + 2, Reason: "param must end a path segment"}
+		}
+
+		p.Segments = append(p.Segments, seg)
+		i = end + 1
+		staticStart = i
+	}
+
+	if staticStart < len(pattern) {
+		p.Segments = append(p.Segments, Segment{
+			Kind:  SegmentStatic,
+			Value: pattern[staticStart:],
+			Start: staticStart,
+			End:   len(pattern),
+		})
+	}
+
+	return p, nil
+}
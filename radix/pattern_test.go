@@ -0,0 +1,174 @@
+package radix_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/makasim/httprouter/radix"
+	"github.com/stretchr/testify/require"
+)
+
+var parsePatternTests = map[string]struct {
+	pattern string
+	exp     []radix.Segment
+}{
+	"Root": {
+		pattern: "/",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/", Start: 0, End: 1},
+		},
+	},
+	"Static": {
+		pattern: "/foo/bar",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/foo/bar", Start: 0, End: 8},
+		},
+	},
+	"Param": {
+		pattern: "/users/{id}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/users/", Start: 0, End: 7},
+			{Kind: radix.SegmentParam, Value: "id", Start: 7, End: 11},
+		},
+	},
+	"ParamInTheMiddle": {
+		pattern: "/users/{id}/orders/{order}/",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/users/", Start: 0, End: 7},
+			{Kind: radix.SegmentParam, Value: "id", Start: 7, End: 11},
+			{Kind: radix.SegmentStatic, Value: "/orders/", Start: 11, End: 19},
+			{Kind: radix.SegmentParam, Value: "order", Start: 19, End: 26},
+			{Kind: radix.SegmentStatic, Value: "/", Start: 26, End: 27},
+		},
+	},
+	"Wildcard": {
+		pattern: "/static/{*path}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/static/", Start: 0, End: 8},
+			{Kind: radix.SegmentWildcard, Value: "path", Start: 8, End: 15},
+		},
+	},
+	"AnonymousWildcard": {
+		pattern: "/{*}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/", Start: 0, End: 1},
+			{Kind: radix.SegmentWildcard, Value: "", Start: 1, End: 4},
+		},
+	},
+	"CatchAll": {
+		pattern: "/static/{**path}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/static/", Start: 0, End: 8},
+			{Kind: radix.SegmentWildcard, Value: "path", Start: 8, End: 16},
+		},
+	},
+	"StaticPrefix": {
+		pattern: "/bar/rpc.{*method}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/bar/rpc.", Start: 0, End: 9},
+			{Kind: radix.SegmentWildcard, Value: "method", Start: 9, End: 18},
+		},
+	},
+	"UTF8": {
+		pattern: "/α/{β}",
+		exp: []radix.Segment{
+			{Kind: radix.SegmentStatic, Value: "/α/", Start: 0, End: 4},
+			{Kind: radix.SegmentParam, Value: "β", Start: 4, End: 8},
+		},
+	},
+}
+
+func TestParsePattern(main *testing.T) {
+	for name, tt := range parsePatternTests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			p, err := radix.ParsePattern(tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.pattern, p.String())
+			require.Equal(t, tt.exp, p.Segments)
+
+			for _, s := range p.Segments {
+				if s.Kind == radix.SegmentStatic {
+					require.Equal(t, s.Value, tt.pattern[s.Start:s.End])
+				}
+			}
+		})
+	}
+}
+
+var parsePatternInvalidTests = map[string]struct {
+	pattern   string
+	expColumn int
+	expErr    string
+}{
+	"Empty":              {pattern: "", expColumn: 1, expErr: `pattern "": empty pattern at column 1`},
+	"NoSlash":            {pattern: "foo", expColumn: 1, expErr: `pattern "foo": pattern must start with / at column 1`},
+	"Unclosed":           {pattern: "/foo/{bar", expColumn: 6, expErr: `pattern "/foo/{bar": unclosed { at column 6`},
+	"UnclosedSlash":      {pattern: "/foo/{bar/baz}", expColumn: 6, expErr: `pattern "/foo/{bar/baz}": unclosed { at column 6`},
+	"Nested":             {pattern: "/{foo{bar}}", expColumn: 2, expErr: `pattern "/{foo{bar}}": unclosed { at column 2`},
+	"UnexpectedClose":    {pattern: "/foo}", expColumn: 5, expErr: `pattern "/foo}": unexpected } at column 5`},
+	"EmptyName":          {pattern: "/foo/{}", expColumn: 6, expErr: `pattern "/foo/{}": empty param name at column 6`},
+	"WildcardNotLast":    {pattern: "/{*path}/foo", expColumn: 2, expErr: `pattern "/{*path}/foo": wildcard must end the pattern at column 2`},
+	"CatchAllNotLast":    {pattern: "/{**a}/b", expColumn: 2, expErr: `pattern "/{**a}/b": wildcard must end the pattern at column 2`},
+	"CatchAllNoSlash":    {pattern: "/static{**path}", expColumn: 8, expErr: `pattern "/static{**path}": catch-all must follow a slash at column 8`},
+	"AsteriskInName":     {pattern: "/{pa*th}", expColumn: 2, expErr: `pattern "/{pa*th}": unexpected * in param name at column 2`},
+	"ParamNotSegmentEnd": {pattern: "/{id}.json", expColumn: 6, expErr: `pattern "/{id}.json": param must end a path segment at column 6`},
+	"AdjacentParams":     {pattern: "/{a}{b}", expColumn: 5, expErr: `pattern "/{a}{b}": param must end a path segment at column 5`},
+}
+
+func TestParsePatternInvalid(main *testing.T) {
+	for name, tt := range parsePatternInvalidTests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			_, err := radix.ParsePattern(tt.pattern)
+			require.EqualError(t, err, tt.expErr)
+
+			var perr *radix.PatternError
+			require.True(t, errors.As(err, &perr))
+			require.Equal(t, tt.pattern, perr.Pattern)
+			require.Equal(t, tt.expColumn, perr.Column)
+		})
+	}
+}
+
+func TestPattern(t *testing.T) {
+	p, err := radix.ParsePattern("/users/{id}/files/{*path}")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"id", "path"}, p.ParamNames())
+	require.True(t, p.HasWildcard())
+
+	p, err = radix.ParsePattern("/users/{id}")
+	require.NoError(t, err)
+	require.False(t, p.HasWildcard())
+
+	p, err = radix.ParsePattern("/users")
+	require.NoError(t, err)
+	require.Empty(t, p.ParamNames())
+}
+
+func FuzzParsePattern(f *testing.F) {
+	f.Add(`/`)
+	f.Add(`/foo/{param}`)
+	f.Add(`/foo/{*param}`)
+	f.Add(`/foo/{param`)
+	f.Fuzz(func(t *testing.T, pattern string) {
+		p, err := radix.ParsePattern(pattern)
+		if err != nil {
+			return
+		}
+
+		end := 0
+		for _, s := range p.Segments {
+			if s.Start != end {
+				t.Fatalf("segments are not contiguous: %v", p.Segments)
+			}
+			end = s.End
+		}
+		if end != len(pattern) {
+			t.Fatalf("segments do not cover the pattern: %v", p.Segments)
+		}
+	})
+}
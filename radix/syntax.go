@@ -0,0 +1,54 @@
+package radix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConvertColonSyntax translates a julienschmidt/httprouter path to the tree syntax:
+// a :name segment becomes {name} and a trailing *name segment becomes the {**name} catch-all,
+// which keeps julienschmidt values: /static/*filepath matches /static/ with "/" and /static/js/app.js with "/js/app.js".
+// Colons and asterisks inside a segment are kept as is.
+func ConvertColonSyntax(path string) (string, error) {
+	if !strings.ContainsAny(path, ":*") {
+		return path, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(path) + 4)
+
+	for i := 0; i < len(path); {
+		c := path[i]
+		if (c != ':' && c != '*') || i == 0 || path[i-1] != '/' {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(path[i:], '/')
+		if end == -1 {
+			end = len(path)
+		} else {
+			end += i
+		}
+
+		name := path[i+1 : end]
+		if name == "" {
+			return "", fmt.Errorf("convert: empty param name at %d: %v", i, path)
+		}
+
+		if c == '*' {
+			if end != len(path) {
+				return "", fmt.Errorf("convert: wildcard must be at the end: %v", path)
+			}
+
+			b.WriteString("{**" + name + "}")
+		} else {
+			b.WriteString("{" + name + "}")
+		}
+
+		i = end
+	}
+
+	return b.String(), nil
+}
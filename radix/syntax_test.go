@@ -0,0 +1,47 @@
+package radix_test
+
+import (
+	"testing"
+
+	"github.com/makasim/httprouter/radix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertColonSyntax(main *testing.T) {
+	type test struct {
+		path   string
+		exp    string
+		expErr string
+	}
+
+	tests := map[string]test{
+		"Static":            {path: "/foo/bar", exp: "/foo/bar"},
+		"Root":              {path: "/", exp: "/"},
+		"Param":             {path: "/users/:id", exp: "/users/{id}"},
+		"Params":            {path: "/users/:id/orders/:order", exp: "/users/{id}/orders/{order}"},
+		"TrailingSlash":     {path: "/users/:id/", exp: "/users/{id}/"},
+		"Wildcard":          {path: "/static/*filepath", exp: "/static/{**filepath}"},
+		"ParamAndWildcard":  {path: "/:user/*path", exp: "/{user}/{**path}"},
+		"ColonInSegment":    {path: "/foo:bar/*x", exp: "/foo:bar/{**x}"},
+		"AsteriskInSegment": {path: "/foo*bar", exp: "/foo*bar"},
+		"Braces":            {path: "/users/{id}", exp: "/users/{id}"},
+		"EmptyParam":        {path: "/users/:/foo", expErr: "convert: empty param name at 7: /users/:/foo"},
+		"EmptyWildcard":     {path: "/static/*", expErr: "convert: empty param name at 8: /static/*"},
+		"WildcardNotLast":   {path: "/static/*path/foo", expErr: "convert: wildcard must be at the end: /static/*path/foo"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			act, err := radix.ConvertColonSyntax(tt.path)
+			if tt.expErr != "" {
+				require.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, act)
+		})
+	}
+}
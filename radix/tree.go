@@ -2,76 +2,176 @@ package radix
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 )
 
 type Tree struct {
-	root Node
+	root   Node
+	routes []Route
+	// dead is the number of routes table slots left by deleted routes, the table is compacted once they are the majority.
+	dead int
+}
+
+// Route is a pattern registered in the tree and its key.
+type Route struct {
+	Pattern string
+	Key     uint64
 }
 
 func NewTree() Tree {
 	return Tree{}
 }
 
-func (t Tree) Insert(path string, key uint64) (tree Tree, err error) {
-	if path == "" {
-		return Tree{}, fmt.Errorf("insert: path empty")
+// Insert returns a copy of the tree with the path added, the tree itself is not modified.
+// An invalid pattern is reported as *PatternError, a conflict with a registered one as *ConflictError.
+func (t Tree) Insert(path string, key uint64) (Tree, error) {
+	return t.insert(path, key, false)
+}
+
+// Upsert works like Insert but replaces the key if the path is already registered.
+// It returns the previous key, or 0 if the path was not registered. Param name conflicts are still reported.
+func (t Tree) Upsert(path string, key uint64) (Tree, uint64, error) {
+	var prev uint64
+	if path != "" {
+		if n := t.root.find(path); n != nil {
+			prev = n.key
+		}
 	}
-	if string(path[0]) != "/" {
-		return Tree{}, fmt.Errorf("insert: path must start with /")
+
+	tree, err := t.insert(path, key, true)
+	if err != nil {
+		return Tree{}, 0, err
 	}
 
-	defer func() {
-		rec := recover()
-		if rec == nil {
-			return
-		}
+	return tree, prev, nil
+}
 
-		if recErr, ok := rec.(error); ok {
-			err = recErr
-		} else {
-			err = fmt.Errorf("%v", rec)
-		}
-	}()
+func (t Tree) insert(path string, key uint64, upsert bool) (Tree, error) {
+	if _, err := ParsePattern(path); err != nil {
+		return Tree{}, err
+	}
+
+	root, err := t.root.insert(path, key, upsert)
+	if c, ok := err.(conflict); ok {
+		return Tree{}, t.conflictError(path, c)
+	} else if err != nil {
+		return Tree{}, err
+	}
+
+	t.root = root
+	return t.setRoute(path, key), nil
+}
+
+func (t Tree) conflictError(pattern string, c conflict) *ConflictError {
+	err := &ConflictError{
+		Err:     c.err,
+		Pattern: pattern,
+		Key:     c.key,
+		Column:  len(pattern) - len(c.rest) + 1,
+	}
+	if c.route > 0 {
+		route := t.routes[c.route-1]
+		err.Existing = route.Pattern
+		err.Key = route.Key
+	}
 
-	t.root = t.root.Insert(path, key)
-	return t, err
+	return err
 }
 
-func (t Tree) Delete(path string) (tree Tree, err error) {
-	defer func() {
-		if rec := recover(); rec != nil {
-			err = fmt.Errorf("%v", rec)
+func (t Tree) setRoute(pattern string, key uint64) Tree {
+	n := t.root.find(pattern)
+	if n == nil {
+		return t
+	}
+
+	route := Route{Pattern: pattern, Key: key}
+	if n.route > 0 {
+		if t.routes[n.route-1] == route {
+			return t
 		}
-	}()
 
+		// the routes table may be shared with other trees and their matched routes, copy it to reuse the slot
+		t.routes = slices.Clone(t.routes)
+		t.routes[n.route-1] = route
+		return t
+	}
+
+	// the routes table may be shared with other trees, never append into its spare capacity
+	t.routes = append(t.routes[:len(t.routes):len(t.routes)], route)
+	n.route = uint32(len(t.routes))
+	return t
+}
+
+// Delete returns a copy of the tree without the path and the key the path was registered with,
+// the tree itself is not modified. ErrPathNotFound is returned if the path is not registered.
+func (t Tree) Delete(path string) (Tree, uint64, error) {
 	if path == "" {
-		return Tree{}, fmt.Errorf("delete: path empty")
+		return Tree{}, 0, fmt.Errorf("delete: path empty")
 	}
 	if string(path[0]) != "/" {
-		return Tree{}, fmt.Errorf("delete: path must start with /")
+		return Tree{}, 0, fmt.Errorf("delete: path must start with /")
+	}
+
+	n := t.root.find(path)
+	if n == nil || n.key == 0 {
+		return Tree{}, 0, fmt.Errorf("delete %q: %w", path, ErrPathNotFound)
+	}
+	key := n.key
+	if n.route > 0 {
+		t.dead++
 	}
 
-	i := longestCommonPrefix(path, t.root.path)
-	if i >= 0 {
-		path = path[i:]
+	path = path[len(t.root.path):]
+	switch {
+	case path == "" && len(t.root.children) == 0:
+		t.root.path = ""
+		t.root.key = 0
+		t.root.route = 0
+	case path == "":
+		t.root.key = 0
+		t.root.route = 0
+	default:
+		t.root = t.root.Delete(path)
+	}
 
-		if path == "" && len(t.root.children) == 0 {
-			t.root.path = ""
-			t.root.key = 0
-			return t, nil
-		}
+	if t.dead > len(t.routes)/2 {
+		t = t.Clone()
+	}
+
+	return t, key, nil
+}
+
+// DeleteKey returns a copy of the tree without the paths registered with the key, and the removed routes.
+func (t Tree) DeleteKey(key uint64) (Tree, []Route) {
+	return t.deleteRoutes(func(route Route) bool {
+		return route.Key == key
+	})
+}
 
-		if path == "" && len(t.root.children) > 0 {
-			t.root.key = 0
-			return t, nil
+// DeletePrefix returns a copy of the tree without the patterns starting with the prefix, and the removed routes.
+// The prefix is matched against pattern text, so DeletePrefix("/legacy/") removes "/legacy/{id}" too.
+func (t Tree) DeletePrefix(prefix string) (Tree, []Route) {
+	return t.deleteRoutes(func(route Route) bool {
+		return strings.HasPrefix(route.Pattern, prefix)
+	})
+}
+
+func (t Tree) deleteRoutes(match func(route Route) bool) (Tree, []Route) {
+	var removed []Route
+	for _, route := range t.Routes() {
+		if !match(route) {
+			continue
 		}
 
-		t.root = t.root.Delete(path)
-		return t, nil
+		// the route comes from the tree, it cannot be missing
+		t, _, _ = t.Delete(route.Pattern)
+		removed = append(removed, route)
 	}
 
-	return t, nil
+	return t, removed
 }
+
 func (t Tree) Search(path string, kv func(n string, v interface{})) uint64 {
 	if path == "" {
 		return 0
@@ -83,6 +183,79 @@ func (t Tree) Search(path string, kv func(n string, v interface{})) uint64 {
 	return t.root.Search(path, kv)
 }
 
+// SearchRoute works like Search but returns the matched route, or nil if nothing matched.
+// The returned route is shared by all searches and must not be modified.
+func (t Tree) SearchRoute(path string, kv func(n string, v interface{})) *Route {
+	if path == "" {
+		return nil
+	}
+	if kv == nil {
+		kv = func(n string, v interface{}) {}
+	}
+
+	return t.route(t.root.searchKV(path, kv))
+}
+
+// SearchCaptures works like SearchRoute but writes params of the matched route to c in the pattern order.
+// c is reset first, reuse it across searches to avoid allocations.
+func (t Tree) SearchCaptures(path string, c *Captures) *Route {
+	c.reset(path)
+	if path == "" {
+		return nil
+	}
+
+	return t.searchCaptures(path, c)
+}
+
+// SearchBytes works like SearchCaptures but takes the path as a byte slice without copying it.
+// Captured values reference path, they must not be used after path is modified, copy them with Captures.AppendValue to keep them.
+func (t Tree) SearchBytes(path []byte, c *Captures) *Route {
+	c.resetBytes(path)
+	if len(path) == 0 {
+		return nil
+	}
+
+	return t.searchCaptures(c.path, c)
+}
+
+func (t Tree) searchCaptures(path string, c *Captures) *Route {
+	m := t.root.search(path, c)
+	if m == nil {
+		return nil
+	}
+
+	c.reverse()
+	return t.route(m)
+}
+
+// Find returns the route registered with exactly the pattern, or nil. Unlike Search it does not match paths.
+// The returned route is shared by all searches and must not be modified.
+func (t Tree) Find(pattern string) *Route {
+	if pattern == "" {
+		return nil
+	}
+
+	return t.route(t.root.find(pattern))
+}
+
+func (t Tree) route(m *Node) *Route {
+	if m == nil || m.route == 0 {
+		return nil
+	}
+
+	return &t.routes[m.route-1]
+}
+
+// Routes returns registered routes in the tree order.
+func (t Tree) Routes() []Route {
+	routes := make([]Route, 0, len(t.routes))
+	t.root.walkRoutes(func(n *Node) {
+		routes = append(routes, t.routes[n.route-1])
+	})
+
+	return routes
+}
+
 func (t Tree) Count() int {
 	return t.root.Count()
 }
@@ -90,6 +263,9 @@ func (t Tree) Count() int {
 func (t Tree) Clone() Tree {
 	cloneTree := t
 	cloneTree.root = t.root.Clone()
+	cloneTree.routes = nil
+	cloneTree.root.cloneRoutes(t.routes, &cloneTree.routes)
+	cloneTree.dead = 0
 
 	return cloneTree
 }
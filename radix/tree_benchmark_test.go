@@ -66,6 +66,21 @@ func Benchmark_GetWithParams(b *testing.B) {
 	}
 }
 
+func Benchmark_GetWithParamsCaptures(b *testing.B) {
+	tree := NewTree()
+	tree, _ = tree.Insert("/api/{version}/data", 1)
+
+	c := NewCaptures(1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if route := tree.SearchCaptures("/api/v1/data", c); route != nil {
+			key = route.Key
+		}
+	}
+}
+
 func Benchmark_Insert(b *testing.B) {
 	tree := NewTree()
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,52 @@
+package radix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeRoutesTable(main *testing.T) {
+	main.Run("UpsertReusesSlot", func(t *testing.T) {
+		tree, err := NewTree().Insert("/users/{id}", 1)
+		require.NoError(t, err)
+
+		prev := tree
+		for i := uint64(2); i < 100; i++ {
+			tree, _, err = tree.Upsert("/users/{id}", i)
+			require.NoError(t, err)
+		}
+
+		require.Len(t, tree.routes, 1)
+		require.Equal(t, uint64(99), tree.SearchRoute("/users/1", nil).Key)
+
+		// the previous tree keeps its table
+		require.Equal(t, uint64(1), prev.SearchRoute("/users/1", nil).Key)
+	})
+
+	main.Run("DeleteCompacts", func(t *testing.T) {
+		tree := NewTree()
+
+		var err error
+		tree, err = tree.Insert("/health", 1)
+		require.NoError(t, err)
+
+		for i := uint64(0); i < 1000; i++ {
+			tree, _, err = tree.Upsert("/users/{id}", i+10)
+			require.NoError(t, err)
+			tree, err = tree.Insert("/orders/{id}", i+20)
+			require.NoError(t, err)
+			tree, _, err = tree.Delete("/orders/{id}")
+			require.NoError(t, err)
+		}
+
+		require.LessOrEqual(t, len(tree.routes), 2*2+1)
+		require.Equal(t, []Route{
+			{Pattern: "/health", Key: 1},
+			{Pattern: "/users/{id}", Key: 1009},
+		}, tree.Routes())
+		require.Equal(t, uint64(1), tree.Search("/health", nil))
+		require.Equal(t, uint64(1009), tree.Search("/users/1", nil))
+		require.Equal(t, uint64(0), tree.Search("/orders/1", nil))
+	})
+}
@@ -1,6 +1,7 @@
 package radix_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/makasim/httprouter/radix"
@@ -10,13 +11,13 @@ import (
 
 func TestTreeInsertEmptyPath(t *testing.T) {
 	tree, err := radix.NewTree().Insert("", 2)
-	require.EqualError(t, err, "insert: path empty")
+	require.EqualError(t, err, `pattern "": empty pattern at column 1`)
 	require.Equal(t, radix.Tree{}, tree)
 }
 
 func TestTreeInsertInvalidPath(t *testing.T) {
 	tree, err := radix.NewTree().Insert("foo", 2)
-	require.EqualError(t, err, "insert: path must start with /")
+	require.EqualError(t, err, `pattern "foo": pattern must start with / at column 1`)
 	require.Equal(t, radix.Tree{}, tree)
 }
 
@@ -26,6 +27,12 @@ func TestTreeInsertEmptyKey(t *testing.T) {
 	require.Equal(t, radix.Tree{}, tree)
 }
 
+func TestTreeInsertCatchAllNoSlash(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/static{**path}", 1)
+	require.EqualError(t, err, `pattern "/static{**path}": catch-all must follow a slash at column 8`)
+	require.Equal(t, radix.Tree{}, tree)
+}
+
 func TestTree(t *testing.T) {
 	tree := radix.NewTree()
 
@@ -57,14 +64,18 @@ func TestTree(t *testing.T) {
 
 	assert.Equal(t, uint64(5), tree.Search("/cc/bb/aa", dummyKV()))
 
-	tree, err = tree.Delete("/faa")
+	tree, key, err := tree.Delete("/faa")
 	require.NoError(t, err)
+	require.Equal(t, uint64(2), key)
 
-	tree, err = tree.Delete("/foooo")
-	require.NoError(t, err)
+	_, _, err = tree.Delete("/foooo")
+	require.ErrorIs(t, err, radix.ErrPathNotFound)
 
-	tree, err = tree.Delete("/fo")
-	require.NoError(t, err)
+	_, _, err = tree.Delete("/fo")
+	require.EqualError(t, err, `delete "/fo": path not found`)
+
+	_, _, err = tree.Delete("/faa")
+	require.ErrorIs(t, err, radix.ErrPathNotFound)
 
 	assert.Equal(t, uint64(0), tree.Search("/faa", dummyKV()))
 	assert.Equal(t, uint64(1), tree.Search("/foo", dummyKV()))
@@ -79,12 +90,46 @@ func TestTreeDelete(t *testing.T) {
 	// guard
 	assert.Equal(t, uint64(1), tree.Search("/foo", dummyKV()))
 
-	tree, err = tree.Delete("/foo")
+	tree, _, err = tree.Delete("/foo")
 	require.NoError(t, err)
 
 	assert.Equal(t, uint64(0), tree.Search("/foo", dummyKV()))
 }
 
+func TestTreeDeleteParamChild(t *testing.T) {
+	tree := radix.NewTree()
+
+	tree, err := tree.Insert("/a/{id}/x", 1)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/a/{id}/y", 2)
+	require.NoError(t, err)
+
+	tree, _, err = tree.Delete("/a/{id}/x")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(0), tree.Search("/a/5/x", dummyKV()))
+	assert.Equal(t, uint64(2), tree.Search("/a/5/y", dummyKV()))
+	assert.Equal(t, []radix.Route{{Pattern: "/a/{id}/y", Key: 2}}, tree.Routes())
+
+	tree, err = tree.Insert("/a/{id}/x", 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), tree.Search("/a/5/x", dummyKV()))
+	assert.Equal(t, uint64(2), tree.Search("/a/5/y", dummyKV()))
+}
+
+func TestTreeFind(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/users/{id}", 1)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/users/{id}/orders", 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, &radix.Route{Pattern: "/users/{id}", Key: 1}, tree.Find("/users/{id}"))
+	assert.Equal(t, &radix.Route{Pattern: "/users/{id}/orders", Key: 2}, tree.Find("/users/{id}/orders"))
+	assert.Nil(t, tree.Find("/users/1"))
+	assert.Nil(t, tree.Find("/users/"))
+	assert.Nil(t, tree.Find(""))
+}
+
 func TestTreeCount(t *testing.T) {
 	t0 := radix.Tree{}
 	assert.Equal(t, 0, t0.Count())
@@ -103,13 +148,13 @@ func TestTreeCount(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 4, t2.Count())
 
-	t2, err = t2.Delete("/foo")
+	t2, _, err = t2.Delete("/foo")
 	require.NoError(t, err)
-	t2, err = t2.Delete("/bar")
+	t2, _, err = t2.Delete("/bar")
 	require.NoError(t, err)
-	t2, err = t2.Delete("/foo/bar")
+	t2, _, err = t2.Delete("/foo/bar")
 	require.NoError(t, err)
-	t2, err = t2.Delete("/{name}")
+	t2, _, err = t2.Delete("/{name}")
 	require.NoError(t, err)
 	assert.Equal(t, 0, t2.Count())
 }
@@ -147,3 +192,437 @@ func FuzzTree_Search(f *testing.F) {
 		})
 	})
 }
+
+func TestTreeSearchRoute(main *testing.T) {
+	main.Run("Match", func(t *testing.T) {
+		tree := radix.NewTree()
+
+		tree, err := tree.Insert("/foo", 1)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/foo/{bar}", 2)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/foo/{bar}/baz", 3)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/static/{*path}", 4)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/fo", 5)
+		require.NoError(t, err)
+
+		assert.Equal(t, &radix.Route{Pattern: "/foo", Key: 1}, tree.SearchRoute("/foo", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/foo/{bar}", Key: 2}, tree.SearchRoute("/foo/123", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/foo/{bar}/baz", Key: 3}, tree.SearchRoute("/foo/123/baz", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/static/{*path}", Key: 4}, tree.SearchRoute("/static/js/app.js", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/fo", Key: 5}, tree.SearchRoute("/fo", nil))
+
+		assert.Nil(t, tree.SearchRoute("/f", nil))
+		assert.Nil(t, tree.SearchRoute("/bar", nil))
+		assert.Nil(t, tree.SearchRoute("", nil))
+	})
+
+	main.Run("SameKeyManyPatterns", func(t *testing.T) {
+		tree := radix.NewTree()
+
+		tree, err := tree.Insert("/foo", 1)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/bar/{id}", 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, &radix.Route{Pattern: "/foo", Key: 1}, tree.SearchRoute("/foo", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/bar/{id}", Key: 1}, tree.SearchRoute("/bar/123", nil))
+	})
+
+	main.Run("Delete", func(t *testing.T) {
+		tree := radix.NewTree()
+
+		tree, err := tree.Insert("/foo", 1)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/foo/bar", 2)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/fo", 3)
+		require.NoError(t, err)
+
+		tree, _, err = tree.Delete("/fo")
+		require.NoError(t, err)
+		assert.Nil(t, tree.SearchRoute("/fo", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/foo", Key: 1}, tree.SearchRoute("/foo", nil))
+
+		tree, _, err = tree.Delete("/foo")
+		require.NoError(t, err)
+		assert.Nil(t, tree.SearchRoute("/foo", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/foo/bar", Key: 2}, tree.SearchRoute("/foo/bar", nil))
+	})
+
+	main.Run("Clone", func(t *testing.T) {
+		tree := radix.NewTree()
+
+		tree, err := tree.Insert("/foo", 1)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/bar", 2)
+		require.NoError(t, err)
+		tree, _, err = tree.Delete("/foo")
+		require.NoError(t, err)
+
+		clone := tree.Clone()
+		clone, err = clone.Insert("/baz", 3)
+		require.NoError(t, err)
+		tree, err = tree.Insert("/qux", 4)
+		require.NoError(t, err)
+
+		assert.Equal(t, &radix.Route{Pattern: "/bar", Key: 2}, clone.SearchRoute("/bar", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/baz", Key: 3}, clone.SearchRoute("/baz", nil))
+		assert.Nil(t, clone.SearchRoute("/qux", nil))
+
+		assert.Equal(t, &radix.Route{Pattern: "/bar", Key: 2}, tree.SearchRoute("/bar", nil))
+		assert.Equal(t, &radix.Route{Pattern: "/qux", Key: 4}, tree.SearchRoute("/qux", nil))
+		assert.Nil(t, tree.SearchRoute("/baz", nil))
+	})
+}
+
+func TestTreeRoutes(t *testing.T) {
+	tree := radix.NewTree()
+	require.Empty(t, tree.Routes())
+
+	tree, err := tree.Insert("/foo", 1)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/foo/{bar}", 2)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/baz", 3)
+	require.NoError(t, err)
+
+	require.Equal(t, []radix.Route{
+		{Pattern: "/foo", Key: 1},
+		{Pattern: "/foo/{bar}", Key: 2},
+		{Pattern: "/baz", Key: 3},
+	}, tree.Routes())
+
+	tree, _, err = tree.Delete("/foo")
+	require.NoError(t, err)
+
+	require.Equal(t, []radix.Route{
+		{Pattern: "/foo/{bar}", Key: 2},
+		{Pattern: "/baz", Key: 3},
+	}, tree.Routes())
+}
+
+func TestTreeInsertConflict(main *testing.T) {
+	type test struct {
+		routes     []string
+		pattern    string
+		expErr     error
+		expMsg     string
+		expPattern string
+		expKey     uint64
+		expColumn  int
+	}
+
+	tests := map[string]test{
+		"SamePath": {
+			routes:     []string{"/foo"},
+			pattern:    "/foo",
+			expErr:     radix.ErrPathAlreadyTaken,
+			expMsg:     `"/foo" conflicts with "/foo" (key 1) at column 1: path already taken`,
+			expPattern: "/foo",
+			expKey:     1,
+			expColumn:  1,
+		},
+		"ChildPath": {
+			routes:     []string{"/foo", "/foo/bar", "/foo/baz"},
+			pattern:    "/foo/baz",
+			expErr:     radix.ErrPathAlreadyTaken,
+			expMsg:     `"/foo/baz" conflicts with "/foo/baz" (key 3) at column 8: path already taken`,
+			expPattern: "/foo/baz",
+			expKey:     3,
+			expColumn:  8,
+		},
+		"Param": {
+			routes:     []string{"/users/{id}", "/users/{id}/posts"},
+			pattern:    "/users/{id}/posts",
+			expErr:     radix.ErrPathAlreadyTaken,
+			expMsg:     `"/users/{id}/posts" conflicts with "/users/{id}/posts" (key 2) at column 12: path already taken`,
+			expPattern: "/users/{id}/posts",
+			expKey:     2,
+			expColumn:  12,
+		},
+		"ParamName": {
+			routes:     []string{"/users/{id}"},
+			pattern:    "/users/{name}",
+			expErr:     radix.ErrParamNameConflict,
+			expMsg:     `"/users/{name}" conflicts with "/users/{id}" (key 1) at column 8: param name conflict`,
+			expPattern: "/users/{id}",
+			expKey:     1,
+			expColumn:  8,
+		},
+		"ParamNameDeep": {
+			routes:     []string{"/users/{id}/posts/{post}"},
+			pattern:    "/users/{name}",
+			expErr:     radix.ErrParamNameConflict,
+			expMsg:     `"/users/{name}" conflicts with "/users/{id}/posts/{post}" (key 1) at column 8: param name conflict`,
+			expPattern: "/users/{id}/posts/{post}",
+			expKey:     1,
+			expColumn:  8,
+		},
+		"Wildcard": {
+			routes:     []string{"/static/{*path}"},
+			pattern:    "/static/{*file}",
+			expErr:     radix.ErrParamNameConflict,
+			expMsg:     `"/static/{*file}" conflicts with "/static/{*path}" (key 1) at column 9: param name conflict`,
+			expPattern: "/static/{*path}",
+			expKey:     1,
+			expColumn:  9,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			tree := radix.NewTree()
+
+			var err error
+			for i, route := range tt.routes {
+				tree, err = tree.Insert(route, uint64(i+1))
+				require.NoError(t, err)
+			}
+
+			_, err = tree.Insert(tt.pattern, 100)
+			require.EqualError(t, err, tt.expMsg)
+			require.ErrorIs(t, err, tt.expErr)
+
+			var conflictErr *radix.ConflictError
+			require.ErrorAs(t, err, &conflictErr)
+			require.Equal(t, tt.pattern, conflictErr.Pattern)
+			require.Equal(t, tt.expPattern, conflictErr.Existing)
+			require.Equal(t, tt.expKey, conflictErr.Key)
+			require.Equal(t, tt.expColumn, conflictErr.Column)
+		})
+	}
+}
+
+func TestTreeInsertConflictAtomic(main *testing.T) {
+	routes := []string{
+		"/",
+		"/api",
+		"/api/v1/users",
+		"/api/v1/users/{id}",
+		"/api/v1/users/{id}/posts",
+		"/api/v1/users/{id}/posts/{post}",
+		"/api/v1/users/{id}/posts/{post}/comments",
+		"/api/v1/users/{id}/posts/{post}/files/{*path}",
+		"/api/v2/{*path}",
+	}
+
+	tree := radix.NewTree()
+	for i, route := range routes {
+		var err error
+		tree, err = tree.Insert(route, uint64(i+1))
+		require.NoError(main, err)
+	}
+
+	expString := tree.String()
+	expRoutes := tree.Routes()
+
+	conflicts := make(map[string]string)
+	for _, route := range routes {
+		// the same pattern with another key conflicts at the route node
+		conflicts[route] = route
+	}
+	conflicts["/api/v1/users/{name}"] = "/api/v1/users/{name}"
+	conflicts["/api/v1/users/{name}/posts/new"] = "/api/v1/users/{name}/posts/new"
+	conflicts["/api/v1/users/{id}/posts/{name}"] = "/api/v1/users/{id}/posts/{name}"
+	conflicts["/api/v1/users/{id}/posts/{post}/files/{*file}"] = "/api/v1/users/{id}/posts/{post}/files/{*file}"
+	conflicts["/api/v2/{*file}"] = "/api/v2/{*file}"
+	// the new static prefix splits nodes before the conflicting param is found
+	conflicts["/api/v1/usersx/{id}/a/{a}"] = ""
+	conflicts["/api/v1/users/{id}/po/{x}"] = ""
+
+	for pattern, expConflict := range conflicts {
+		pattern := pattern
+		expConflict := expConflict
+
+		main.Run(pattern, func(t *testing.T) {
+			newTree, err := tree.Insert(pattern, 100)
+			if expConflict == "" {
+				require.NoError(t, err)
+				require.Equal(t, uint64(100), newTree.Search(strings.NewReplacer("{id}", "1", "{a}", "2", "{x}", "3").Replace(pattern), dummyKV()))
+			} else {
+				var conflictErr *radix.ConflictError
+				require.ErrorAs(t, err, &conflictErr)
+				require.Equal(t, expConflict, conflictErr.Pattern)
+				require.Equal(t, radix.Tree{}, newTree)
+			}
+
+			require.Equal(t, expString, tree.String())
+			require.Equal(t, expRoutes, tree.Routes())
+			require.Equal(t, uint64(6), tree.Search("/api/v1/users/1/posts/2", dummyKV()))
+		})
+	}
+}
+
+func TestTreeDeleteCopy(t *testing.T) {
+	tree := radix.NewTree()
+	for i, route := range []string{"/foo", "/foo/bar", "/foo/baz", "/foo/{id}", "/foo/{id}/qux"} {
+		var err error
+		tree, err = tree.Insert(route, uint64(i+1))
+		require.NoError(t, err)
+	}
+
+	expString := tree.String()
+	for _, path := range []string{"/foo", "/foo/bar", "/foo/baz", "/foo/{id}", "/foo/{id}/qux"} {
+		newTree, key, err := tree.Delete(path)
+		require.NoError(t, err)
+		require.NotZero(t, key)
+		require.Len(t, newTree.Routes(), 4)
+		for _, route := range newTree.Routes() {
+			require.NotEqual(t, path, route.Pattern)
+		}
+
+		require.Equal(t, expString, tree.String())
+	}
+
+	// a prefix of the root path is not a route
+	tree, err := radix.NewTree().Insert("/foo", 1)
+	require.NoError(t, err)
+
+	_, _, err = tree.Delete("/fo")
+	require.ErrorIs(t, err, radix.ErrPathNotFound)
+	require.Equal(t, uint64(1), tree.Search("/foo", dummyKV()))
+}
+
+func TestTreeInsertSharedRoutes(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/foo", 1)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/bar", 2)
+	require.NoError(t, err)
+	tree, err = tree.Insert("/baz", 3)
+	require.NoError(t, err)
+
+	// the trees share the routes table with spare capacity
+	t1, err := tree.Insert("/one", 4)
+	require.NoError(t, err)
+	t2, err := tree.Insert("/two", 5)
+	require.NoError(t, err)
+
+	require.Equal(t, "/one", t1.SearchRoute("/one", nil).Pattern)
+	require.Equal(t, "/two", t2.SearchRoute("/two", nil).Pattern)
+}
+
+func TestTreeUpsert(t *testing.T) {
+	tree, err := radix.NewTree().Insert("/users/{id}", 1)
+	require.NoError(t, err)
+
+	tree, prev, err := tree.Upsert("/users", 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), prev)
+
+	newTree, prev, err := tree.Upsert("/users/{id}", 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), prev)
+	require.Equal(t, uint64(3), newTree.Search("/users/1", dummyKV()))
+	require.Equal(t, &radix.Route{Pattern: "/users/{id}", Key: 3}, newTree.SearchRoute("/users/1", nil))
+	require.Len(t, newTree.Routes(), 2)
+
+	// the original tree is not modified
+	require.Equal(t, uint64(1), tree.Search("/users/1", dummyKV()))
+
+	_, _, err = tree.Upsert("/users/{name}", 4)
+	require.ErrorIs(t, err, radix.ErrParamNameConflict)
+
+	_, _, err = tree.Upsert("", 4)
+	require.EqualError(t, err, `pattern "": empty pattern at column 1`)
+}
+
+func TestTreeDeleteKey(t *testing.T) {
+	tree := radix.NewTree()
+	for pattern, key := range map[string]uint64{
+		"/users":              1,
+		"/users/{id}":         2,
+		"/users/{id}/posts":   1,
+		"/legacy/{*path}":     1,
+		"/legacy/users/{id}":  3,
+		"/legacy/users/admin": 3,
+	} {
+		var err error
+		tree, err = tree.Insert(pattern, key)
+		require.NoError(t, err)
+	}
+
+	newTree, removed := tree.DeleteKey(1)
+	require.ElementsMatch(t, []radix.Route{
+		{Pattern: "/users", Key: 1},
+		{Pattern: "/users/{id}/posts", Key: 1},
+		{Pattern: "/legacy/{*path}", Key: 1},
+	}, removed)
+	require.Len(t, newTree.Routes(), 3)
+	require.Equal(t, uint64(0), newTree.Search("/users", dummyKV()))
+	require.Equal(t, uint64(2), newTree.Search("/users/1", dummyKV()))
+
+	// the original tree is not modified
+	require.Len(t, tree.Routes(), 6)
+
+	newTree, removed = tree.DeleteKey(100)
+	require.Empty(t, removed)
+	require.Equal(t, tree.String(), newTree.String())
+}
+
+func TestTreeDeletePrefix(t *testing.T) {
+	tree := radix.NewTree()
+	for i, pattern := range []string{
+		"/legacy",
+		"/legacy/{*path}",
+		"/legacy/users/{id}",
+		"/legacy/users/admin",
+		"/legacyx",
+		"/users/{id}",
+	} {
+		var err error
+		tree, err = tree.Insert(pattern, uint64(i+1))
+		require.NoError(t, err)
+	}
+
+	newTree, removed := tree.DeletePrefix("/legacy/")
+	require.ElementsMatch(t, []radix.Route{
+		{Pattern: "/legacy/{*path}", Key: 2},
+		{Pattern: "/legacy/users/{id}", Key: 3},
+		{Pattern: "/legacy/users/admin", Key: 4},
+	}, removed)
+	require.ElementsMatch(t, []radix.Route{
+		{Pattern: "/legacy", Key: 1},
+		{Pattern: "/legacyx", Key: 5},
+		{Pattern: "/users/{id}", Key: 6},
+	}, newTree.Routes())
+	require.Equal(t, uint64(0), newTree.Search("/legacy/users/admin", dummyKV()))
+
+	newTree, removed = tree.DeletePrefix("/")
+	require.Len(t, removed, 6)
+	require.Equal(t, 0, newTree.Count())
+
+	// the original tree is not modified
+	require.Len(t, tree.Routes(), 6)
+}
+
+func TestTreeInsertParsePattern(main *testing.T) {
+	for name, tt := range parsePatternTests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			_, err := radix.ParsePattern(tt.pattern)
+			require.NoError(t, err)
+
+			_, err = radix.NewTree().Insert(tt.pattern, 1)
+			require.NoError(t, err)
+		})
+	}
+
+	for name, tt := range parsePatternInvalidTests {
+		tt := tt
+
+		main.Run(name, func(t *testing.T) {
+			_, perr := radix.ParsePattern(tt.pattern)
+			require.Error(t, perr)
+
+			tree, err := radix.NewTree().Insert(tt.pattern, 1)
+			require.Equal(t, perr, err)
+			require.Equal(t, radix.Tree{}, tree)
+		})
+	}
+}
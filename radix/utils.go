@@ -113,18 +113,19 @@ func findSlashOrEnd(a string) int {
 	return size
 }
 
-func updateParamNode(pn Node, path string, key uint64) Node {
+func updateParamNode(pn Node, path string, key uint64, upsert bool) (Node, error) {
+	rest := path
 	end := findParamEnd(path)
 	if end == -1 {
-		panic(fmt.Sprintf("no right bracket: %v", path))
+		return Node{}, fmt.Errorf("no right bracket: %v", path)
 	}
 
 	if pn.kind != param {
-		panic("node must be kind param")
+		return Node{}, fmt.Errorf("node must be kind param")
 	}
 
 	if pn.path != "" && pn.path != path[:end] {
-		panic(ErrParamNameConflict)
+		return Node{}, paramConflict(pn, rest)
 	}
 
 	if pn.path == "" {
@@ -133,16 +134,15 @@ func updateParamNode(pn Node, path string, key uint64) Node {
 
 	path = path[end:]
 	if path != "" {
-		pn = pn.Insert(path, key)
-	} else {
-		if pn.key > 0 && pn.key != key {
-			panic(ErrPathAlreadyTaken)
-		}
+		return pn.insert(path, key, upsert)
+	}
 
-		pn.key = key
+	if !upsert && pn.key > 0 && pn.key != key {
+		return Node{}, pathTaken(pn, rest)
 	}
 
-	return pn
+	pn.key = key
+	return pn, nil
 }
 
 func min(a, b int) int {
@@ -1,7 +1,12 @@
 package httprouter
 
 import (
+	"context"
 	"fmt"
+	"runtime/debug"
+	"runtime/pprof"
+	"slices"
+	"sync"
 
 	"github.com/makasim/httprouter/radix"
 	"github.com/savsgio/gotils"
@@ -10,13 +15,72 @@ import (
 
 var HandlerKeyUserValue = "fasthttprouter.handler_id"
 
+// MatchedRouteUserValue is the user value key under which the matched *radix.Route is stored
+// when Router.SaveMatchedRoute is enabled.
+const MatchedRouteUserValue = "fasthttprouter.matched_route"
+
+// PanicInfo describes a panic recovered by the router.
+type PanicInfo struct {
+	Value interface{}
+	Stack []byte
+	// Pattern and Key of the matched route, empty if the panic happened before a route matched or none did.
+	Pattern string
+	Key     uint64
+}
+
+var capturesPool = sync.Pool{
+	New: func() interface{} {
+		return radix.NewCaptures(8)
+	},
+}
+
 type Router struct {
 	PageNotFoundHandler     fasthttp.RequestHandler
 	MethodNotAllowedHandler fasthttp.RequestHandler
 	GlobalHandler           fasthttp.RequestHandler
-	Handlers                map[uint64]fasthttp.RequestHandler
+	// Handlers maps handler ids chosen by the caller to handlers.
+	// Handlers added with AddHandler take precedence, do not mix ids from both.
+	//
+	// Deprecated: use AddHandler or RegisterHandler, the registry is backed by a slice and dispatches faster.
+	Handlers map[uint64]fasthttp.RequestHandler
+
+	// PanicHandler, if set, recovers panics from handlers and responds to the client.
+	PanicHandler func(*fasthttp.RequestCtx, PanicInfo)
+
+	// ParamErrorHandler, if set, is called by HandleParamError instead of replying with 400 Bad Request.
+	ParamErrorHandler func(*fasthttp.RequestCtx, error)
+
+	// ErrorHandler, if set, handles errors returned by handlers registered with RegisterErrorHandler.
+	ErrorHandler func(*fasthttp.RequestCtx, RouteInfo, error)
+
+	// ColonSyntax makes Add and Remove accept julienschmidt/httprouter paths:
+	// :name and *name segments are translated to {name} and the {**name} catch-all,
+	// so like in julienschmidt/httprouter /static/*filepath matches /static/ and its value keeps the leading slash.
+	// As for any wildcard, the user value key keeps the asterisk: ctx.UserValue("*filepath"),
+	// while BindCtx and the `path:"filepath"` tag accept the name without it.
+	ColonSyntax bool
+
+	// SaveMatchedRoute stores the matched route under MatchedRouteUserValue before invoking the handler.
+	SaveMatchedRoute bool
+
+	// ProfileLabels makes Handle run the matched handler under pprof.Do with route and method labels,
+	// so CPU and goroutine profiles can be split by route pattern.
+	ProfileLabels bool
+
+	// CopyParams makes Handle copy param values before storing them as user values.
+	// By default the []byte values reference the request path buffer, which fasthttp reuses for later requests,
+	// so a handler must copy a value it keeps after returning.
+	CopyParams bool
+
+	// PooledParams makes Handle store a single pooled *RequestParams under ParamsUserValue
+	// instead of a user value per param and HandlerKeyUserValue. Use ParamsFromCtx to get it.
+	PooledParams bool
 
 	Trees []radix.Tree
+
+	handlers       []fasthttp.RequestHandler
+	freeHandlerIDs []uint64
+	meta           map[uint64]Meta
 }
 
 func New() *Router {
@@ -28,28 +92,65 @@ func New() *Router {
 			ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
 		},
 		Handlers: make(map[uint64]fasthttp.RequestHandler),
+		handlers: make([]fasthttp.RequestHandler, 1), // 0 is nil handler
+		meta:     make(map[uint64]Meta),
 
 		Trees: make([]radix.Tree, 9),
 	}
 }
 
 func (r *Router) Handle(ctx *fasthttp.RequestCtx) {
+	var route *radix.Route
+	if r.PanicHandler != nil {
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.handlePanic(ctx, route, rec)
+			}
+		}()
+	}
+
 	i := r.methodIndexOf(gotils.B2S(ctx.Method()))
 	if i == -1 {
 		r.MethodNotAllowedHandler(ctx)
 		return
 	}
 
-	hID := r.Trees[i].Search(gotils.B2S(ctx.Path()), func(n string, v interface{}) {
-		ctx.SetUserValue(n, v)
-	})
-	if hID == 0 {
+	c := capturesPool.Get().(*radix.Captures)
+	route = r.Trees[i].SearchBytes(ctx.Path(), c)
+	if route != nil {
+		r.setParams(ctx, route, c)
+	}
+	capturesPool.Put(c)
+
+	if route == nil {
 		r.PageNotFoundHandler(ctx)
 		return
 	}
 
-	ctx.SetUserValue(HandlerKeyUserValue, hID)
+	hID := route.Key
+	if r.SaveMatchedRoute {
+		ctx.SetUserValue(MatchedRouteUserValue, route)
+	}
+
+	if r.ProfileLabels {
+		// methods[i] instead of ctx.Method(), profiles keep the labels after fasthttp reuses the ctx buffers
+		labels := pprof.Labels("route", route.Pattern, "method", methods[i])
+		pprof.Do(ctx, labels, func(context.Context) {
+			r.dispatch(ctx, hID)
+		})
+		return
+	}
+
+	r.dispatch(ctx, hID)
+}
 
+func (r *Router) dispatch(ctx *fasthttp.RequestCtx, hID uint64) {
+	if hID < uint64(len(r.handlers)) {
+		if h := r.handlers[hID]; h != nil {
+			h(ctx)
+			return
+		}
+	}
 	if h, ok := r.Handlers[hID]; ok {
 		h(ctx)
 		return
@@ -63,6 +164,160 @@ func (r *Router) Handle(ctx *fasthttp.RequestCtx) {
 	r.PageNotFoundHandler(ctx)
 }
 
+func (r *Router) setParams(ctx *fasthttp.RequestCtx, route *radix.Route, c *radix.Captures) {
+	if r.PooledParams {
+		ps := requestParamsPool.Get().(*RequestParams)
+		ps.set(route, c, r.CopyParams)
+		ctx.SetUserValue(ParamsUserValue, ps)
+
+		return
+	}
+
+	for j := 0; j < c.Len(); j++ {
+		if r.CopyParams {
+			ctx.SetUserValue(c.At(j).Name, c.AppendValue(nil, j))
+		} else {
+			ctx.SetUserValue(c.At(j).Name, c.ValueBytes(j))
+		}
+	}
+	ctx.SetUserValue(HandlerKeyUserValue, route.Key)
+}
+
+// Lookup resolves method and path the same way Handle does without serving a request.
+// It returns the handler key and the pattern of the matched route and the captured params in the pattern order.
+// Param values share memory with path.
+func (r *Router) Lookup(method, path string) (key uint64, pattern string, ps Params, err error) {
+	i := r.methodIndexOf(method)
+	if i == -1 {
+		return 0, "", nil, fmt.Errorf("method not allowed")
+	}
+
+	c := capturesPool.Get().(*radix.Captures)
+	defer capturesPool.Put(c)
+
+	route := r.Trees[i].SearchCaptures(path, c)
+	if route == nil {
+		return 0, "", nil, fmt.Errorf("path %v not found", path)
+	}
+
+	if c.Len() > 0 {
+		ps = make(Params, 0, c.Len())
+		for j := 0; j < c.Len(); j++ {
+			ps = append(ps, Param{Key: c.At(j).Name, Value: c.Value(j)})
+		}
+	}
+
+	return route.Key, route.Pattern, ps, nil
+}
+
+func (r *Router) handlePanic(ctx *fasthttp.RequestCtx, route *radix.Route, rec interface{}) {
+	info := PanicInfo{
+		Value: rec,
+		Stack: debug.Stack(),
+	}
+	if route != nil {
+		info.Pattern = route.Pattern
+		info.Key = route.Key
+	}
+
+	r.PanicHandler(ctx, info)
+}
+
+// AddHandler adds handler wrapped with the given middleware, the first one being the outermost.
+// The returned id is used to register routes with Add. Ids of removed handlers are reused once no route points at them,
+// ids set in the deprecated Handlers map are never handed out.
+func (r *Router) AddHandler(handler fasthttp.RequestHandler, middleware ...Middleware) uint64 {
+	if handler == nil {
+		panic("handler is nil")
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	for i := len(r.freeHandlerIDs) - 1; i >= 0; i-- {
+		id := r.freeHandlerIDs[i]
+		if r.hasRoutes(id) {
+			// dangling routes would be served by the new handler
+			continue
+		}
+		if _, ok := r.Handlers[id]; ok {
+			continue
+		}
+
+		r.freeHandlerIDs = slices.Delete(r.freeHandlerIDs, i, i+1)
+		r.handlers[id] = handler
+
+		return id
+	}
+
+	// the registry takes precedence in dispatch, so it would shadow handlers stored in the map
+	for {
+		if _, ok := r.Handlers[uint64(len(r.handlers))]; !ok {
+			break
+		}
+		r.handlers = append(r.handlers, nil)
+	}
+
+	id := len(r.handlers)
+	r.handlers = append(r.handlers, handler)
+
+	return uint64(id)
+}
+
+// hasRoutes reports whether a route in any method tree points at the handler id.
+func (r *Router) hasRoutes(handlerID uint64) bool {
+	for i := range r.Trees {
+		for _, route := range r.Trees[i].Routes() {
+			if route.Key == handlerID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GetHandler returns the handler added with AddHandler.
+func (r *Router) GetHandler(handlerID uint64) (fasthttp.RequestHandler, error) {
+	if handlerID < 1 || handlerID >= uint64(len(r.handlers)) || r.handlers[handlerID] == nil {
+		return nil, fmt.Errorf("handler not found")
+	}
+
+	return r.handlers[handlerID], nil
+}
+
+// RemoveHandler frees the handler id for reuse and drops its meta. Routes registered for it are kept
+// and fall through to GlobalHandler or PageNotFoundHandler, AddHandler does not hand the id out again until RemoveKey removes them.
+// Removing an unknown or already removed id fails without changing the router.
+func (r *Router) RemoveHandler(handlerID uint64) error {
+	if handlerID < 1 || handlerID >= uint64(len(r.handlers)) || r.handlers[handlerID] == nil {
+		return fmt.Errorf("handler %d not found", handlerID)
+	}
+
+	r.handlers[handlerID] = nil
+	delete(r.meta, handlerID)
+	r.freeHandlerIDs = append(r.freeHandlerIDs, handlerID)
+
+	return nil
+}
+
+// RegisterHandler adds handler and registers a route for method and path with its id.
+// The handler is removed if the route cannot be added.
+func (r *Router) RegisterHandler(method, path string, handler fasthttp.RequestHandler, middleware ...Middleware) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+
+	hID := r.AddHandler(handler, middleware...)
+	if err := r.Add(method, path, hID); err != nil {
+		_ = r.RemoveHandler(hID)
+		return err
+	}
+
+	return nil
+}
+
 // Add adds a route for method and path to the router
 // It is not safe for concurrent use.
 // Add routes before using Handle or protect Add, Remove, Handle with mutex.
@@ -75,12 +330,16 @@ func (r *Router) Add(method, path string, handlerID uint64) error {
 		return fmt.Errorf("path empty")
 	}
 
-	var err error
+	path, err := r.convertPath(path)
+	if err != nil {
+		return err
+	}
+
 	tree := r.Trees[methodIndex].Clone()
 
 	tree, err = tree.Insert(path, handlerID)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s: %w", method, err)
 	}
 
 	r.Trees[methodIndex] = tree
@@ -88,6 +347,34 @@ func (r *Router) Add(method, path string, handlerID uint64) error {
 	return nil
 }
 
+// Replace adds a route for method and path or swaps the handler id of an existing one in a single step,
+// so there is no moment when the route is missing like with Remove followed by Add.
+// It returns the previous handler id, or 0 if the route did not exist.
+// It is not safe for concurrent use, see Add.
+func (r *Router) Replace(method, path string, handlerID uint64) (uint64, error) {
+	methodIndex := r.methodIndexOf(method)
+	if methodIndex == -1 {
+		return 0, fmt.Errorf("method not allowed")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("path empty")
+	}
+
+	path, err := r.convertPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	tree, prev, err := r.Trees[methodIndex].Clone().Upsert(path, handlerID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", method, err)
+	}
+
+	r.Trees[methodIndex] = tree
+
+	return prev, nil
+}
+
 // Remove removes a route for method and path frmo the router
 // It is not safe for concurrent use.
 // Remove routes before using Handle or protect Add, Remove, Handle with mutex.
@@ -100,18 +387,74 @@ func (r *Router) Remove(method, path string) error {
 		return fmt.Errorf("path empty")
 	}
 
-	var err error
-	tree := r.Trees[methodIndex].Clone()
-
-	tree, err = tree.Delete(path)
+	path, err := r.convertPath(path)
 	if err != nil {
 		return err
 	}
 
+	tree, _, err := r.Trees[methodIndex].Clone().Delete(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
 	r.Trees[methodIndex] = tree
 	return nil
 }
 
+// RemoveKey removes every route registered for the handler id in all method trees.
+// It returns the number of removed routes.
+// It is not safe for concurrent use, see Remove.
+func (r *Router) RemoveKey(handlerID uint64) int {
+	n := 0
+	for i := range r.Trees {
+		var removed []radix.Route
+		r.Trees[i], removed = r.Trees[i].DeleteKey(handlerID)
+		n += len(removed)
+	}
+
+	return n
+}
+
+// RemovePrefix removes every route which pattern starts with prefix in all method trees.
+// It returns the number of removed routes.
+// It is not safe for concurrent use, see Remove.
+func (r *Router) RemovePrefix(prefix string) int {
+	n := 0
+	for i := range r.Trees {
+		var removed []radix.Route
+		r.Trees[i], removed = r.Trees[i].DeletePrefix(prefix)
+		n += len(removed)
+	}
+
+	return n
+}
+
+func (r *Router) convertPath(path string) (string, error) {
+	if !r.ColonSyntax {
+		return path, nil
+	}
+
+	return radix.ConvertColonSyntax(path)
+}
+
+// MatchedRoute returns the route matched for the request, or nil if Router.SaveMatchedRoute is disabled.
+func MatchedRoute(ctx *fasthttp.RequestCtx) *radix.Route {
+	route, _ := ctx.UserValue(MatchedRouteUserValue).(*radix.Route)
+	return route
+}
+
+var methods = []string{
+	fasthttp.MethodGet,
+	fasthttp.MethodHead,
+	fasthttp.MethodPost,
+	fasthttp.MethodPut,
+	fasthttp.MethodPatch,
+	fasthttp.MethodDelete,
+	fasthttp.MethodConnect,
+	fasthttp.MethodOptions,
+	fasthttp.MethodTrace,
+}
+
 func (r *Router) methodIndexOf(method string) int {
 	switch method {
 	case fasthttp.MethodGet:
@@ -1,11 +1,14 @@
 package httprouter_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"runtime/pprof"
 	"testing"
 
 	"github.com/makasim/httprouter"
+	"github.com/makasim/httprouter/radix"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 
@@ -34,6 +37,24 @@ func TestRouter_Add(main *testing.T) {
 		require.EqualError(t, err, "path empty")
 	})
 
+	main.Run("Conflict", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		err := r.Add("GET", "/users/{name}/posts", 11)
+		require.EqualError(t, err, `GET: "/users/{name}/posts" conflicts with "/users/{id}" (key 10) at column 8: param name conflict`)
+		require.ErrorIs(t, err, radix.ErrParamNameConflict)
+
+		var conflictErr *radix.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.Equal(t, "/users/{id}", conflictErr.Existing)
+		require.Equal(t, uint64(10), conflictErr.Key)
+
+		// other methods have their own trees
+		require.NoError(t, r.Add("POST", "/users/{name}/posts", 11))
+	})
+
 	main.Run("OK", func(t *testing.T) {
 		r := httprouter.New()
 
@@ -547,6 +568,253 @@ func TestRouter_Remove(t *testing.T) {
 	require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
 }
 
+func TestRouter_AddHandler(main *testing.T) {
+	main.Run("OK", func(t *testing.T) {
+		r := httprouter.New()
+
+		h1ID := r.AddHandler(writeHandler("one"))
+		h2ID := r.AddHandler(writeHandler("two"), writeMiddleware("a"), writeMiddleware("b"))
+		require.Equal(t, uint64(1), h1ID)
+		require.Equal(t, uint64(2), h2ID)
+
+		require.NoError(t, r.Add("GET", "/one", h1ID))
+		require.NoError(t, r.Add("GET", "/two", h2ID))
+
+		require.Equal(t, "one", string(serve(r, "GET", "/one").Response.Body()))
+		require.Equal(t, "abtwo", string(serve(r, "GET", "/two").Response.Body()))
+	})
+
+	main.Run("Reuse", func(t *testing.T) {
+		r := httprouter.New()
+
+		h1ID := r.AddHandler(writeHandler("one"))
+		h2ID := r.AddHandler(writeHandler("two"))
+		h3ID := r.AddHandler(writeHandler("three"))
+
+		require.NoError(t, r.RemoveHandler(h2ID))
+		require.EqualError(t, r.RemoveHandler(h2ID), fmt.Sprintf("handler %d not found", h2ID))
+		require.NoError(t, r.RemoveHandler(h1ID))
+
+		for _, id := range []uint64{0, 100} {
+			require.EqualError(t, r.RemoveHandler(id), fmt.Sprintf("handler %d not found", id))
+		}
+
+		require.Equal(t, h1ID, r.AddHandler(writeHandler("four")))
+		require.Equal(t, h2ID, r.AddHandler(writeHandler("five")))
+		require.Equal(t, h3ID+1, r.AddHandler(writeHandler("six")))
+	})
+
+	main.Run("Nil", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.PanicsWithValue(t, "handler is nil", func() {
+			r.AddHandler(nil)
+		})
+	})
+
+	main.Run("SkipsHandlersMapIDs", func(t *testing.T) {
+		r := httprouter.New()
+		r.Handlers[1] = writeHandler("map")
+		r.Handlers[2] = writeHandler("map")
+
+		require.Equal(t, uint64(3), r.AddHandler(writeHandler("registry")))
+		require.NoError(t, r.Add("GET", "/one", 1))
+		require.NoError(t, r.Add("GET", "/three", 3))
+
+		require.Equal(t, "map", string(serve(r, "GET", "/one").Response.Body()))
+		require.Equal(t, "registry", string(serve(r, "GET", "/three").Response.Body()))
+
+		// the registry takes precedence when the map is set afterwards
+		r.Handlers[3] = writeHandler("map")
+		require.Equal(t, "registry", string(serve(r, "GET", "/three").Response.Body()))
+	})
+
+	main.Run("DanglingRoutes", func(t *testing.T) {
+		r := httprouter.New()
+		r.GlobalHandler = writeHandler("global")
+
+		hID := r.AddHandler(writeHandler("user"))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+
+		require.NoError(t, r.RemoveHandler(hID))
+		require.Equal(t, "global", string(serve(r, "GET", "/users/1").Response.Body()))
+
+		// the id is not reused while routes point at it
+		newID := r.AddHandler(writeHandler("new"))
+		require.NotEqual(t, hID, newID)
+		require.Equal(t, "global", string(serve(r, "GET", "/users/1").Response.Body()))
+
+		require.Equal(t, 1, r.RemoveKey(hID))
+		require.Equal(t, hID, r.AddHandler(writeHandler("reused")))
+	})
+}
+
+func TestRouter_GetHandler(t *testing.T) {
+	r := httprouter.New()
+	r.SetMeta(1, httprouter.Meta{Name: "one"})
+
+	hID := r.AddHandler(writeHandler("one"))
+
+	h, err := r.GetHandler(hID)
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	for _, id := range []uint64{0, 2, 100} {
+		_, err = r.GetHandler(id)
+		require.EqualError(t, err, "handler not found")
+	}
+
+	require.NoError(t, r.RemoveHandler(hID))
+	_, err = r.GetHandler(hID)
+	require.EqualError(t, err, "handler not found")
+
+	_, ok := r.Meta(hID)
+	require.False(t, ok)
+}
+
+func TestRouter_RegisterHandler(main *testing.T) {
+	main.Run("OK", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", writeHandler("user"), writeMiddleware("a")))
+
+		ctx := serve(r, "GET", "/users/123")
+		require.Equal(t, "auser", string(ctx.Response.Body()))
+		require.Equal(t, uint64(1), ctx.UserValue(httprouter.HandlerKeyUserValue))
+	})
+
+	main.Run("AddFailed", func(t *testing.T) {
+		r := httprouter.New()
+
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", writeHandler("user")))
+		require.ErrorIs(t, r.RegisterHandler("GET", "/users/{name}", writeHandler("other")), radix.ErrParamNameConflict)
+
+		// the handler of the failed registration is removed
+		_, err := r.GetHandler(2)
+		require.EqualError(t, err, "handler not found")
+
+		require.EqualError(t, r.RegisterHandler("GET", "/users", nil), "handler is nil")
+	})
+}
+
+func TestRouter_Lookup(main *testing.T) {
+	r := httprouter.New()
+	require.NoError(main, r.Add("GET", "/users/{id}/files/{*path}", 1))
+	require.NoError(main, r.Add("GET", "/users/{id}", 2))
+	require.NoError(main, r.Add("GET", "/static/{*}", 3))
+
+	main.Run("Params", func(t *testing.T) {
+		key, pattern, ps, err := r.Lookup("GET", "/users/123/files/a/b.txt")
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), key)
+		require.Equal(t, "/users/{id}/files/{*path}", pattern)
+		require.Equal(t, httprouter.Params{
+			{Key: "id", Value: "123"},
+			{Key: "*path", Value: "a/b.txt"},
+		}, ps)
+		require.Equal(t, "123", ps.Get("id"))
+		require.Equal(t, "", ps.Get("path"))
+	})
+
+	main.Run("Static", func(t *testing.T) {
+		key, pattern, ps, err := r.Lookup("GET", "/static/app.js")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), key)
+		require.Equal(t, "/static/{*}", pattern)
+		require.Equal(t, httprouter.Params{{Key: "*", Value: "app.js"}}, ps)
+	})
+
+	main.Run("SameAsHandle", func(t *testing.T) {
+		_, _, ps, err := r.Lookup("GET", "/users/123")
+		require.NoError(t, err)
+
+		ctx := serve(r, "GET", "/users/123")
+		for _, p := range ps {
+			require.Equal(t, []byte(p.Value), ctx.UserValue(p.Key))
+		}
+	})
+
+	main.Run("NotFound", func(t *testing.T) {
+		_, _, _, err := r.Lookup("GET", "/foo")
+		require.EqualError(t, err, "path /foo not found")
+
+		// trees are per method, there is no fallback to another method
+		_, _, _, err = r.Lookup("POST", "/users/123")
+		require.EqualError(t, err, "path /users/123 not found")
+	})
+
+	main.Run("MethodNotAllowed", func(t *testing.T) {
+		_, _, _, err := r.Lookup("BREW", "/users/123")
+		require.EqualError(t, err, "method not allowed")
+	})
+}
+
+func TestRouter_Replace(t *testing.T) {
+	r := httprouter.New()
+	r.Handlers[1] = writeHandler("one")
+	r.Handlers[2] = writeHandler("two")
+
+	prev, err := r.Replace("GET", "/foo/{bar}", 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), prev)
+	require.Equal(t, "one", string(serve(r, "GET", "/foo/baz").Response.Body()))
+
+	prev, err = r.Replace("GET", "/foo/{bar}", 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), prev)
+	require.Equal(t, "two", string(serve(r, "GET", "/foo/baz").Response.Body()))
+
+	_, err = r.Replace("GET", "/foo/{baz}", 1)
+	require.ErrorIs(t, err, radix.ErrParamNameConflict)
+	require.Equal(t, "two", string(serve(r, "GET", "/foo/baz").Response.Body()))
+
+	_, err = r.Replace("unsupported", "/foo", 1)
+	require.EqualError(t, err, "method not allowed")
+}
+
+func TestRouter_RemoveNotFound(t *testing.T) {
+	r := httprouter.New()
+
+	require.NoError(t, r.Add("GET", "/foo", 1))
+
+	err := r.Remove("POST", "/foo")
+	require.EqualError(t, err, `POST: delete "/foo": path not found`)
+	require.ErrorIs(t, err, radix.ErrPathNotFound)
+}
+
+func TestRouter_RemoveKey(t *testing.T) {
+	r := httprouter.New()
+	r.Handlers[1] = writeHandler("one")
+	r.Handlers[2] = writeHandler("two")
+
+	require.NoError(t, r.Add("GET", "/foo", 1))
+	require.NoError(t, r.Add("POST", "/foo", 1))
+	require.NoError(t, r.Add("GET", "/foo/{bar}", 1))
+	require.NoError(t, r.Add("GET", "/bar", 2))
+
+	require.Equal(t, 3, r.RemoveKey(1))
+	require.Equal(t, 0, r.RemoveKey(1))
+
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/foo").Response.StatusCode())
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "POST", "/foo").Response.StatusCode())
+	require.Equal(t, "two", string(serve(r, "GET", "/bar").Response.Body()))
+}
+
+func TestRouter_RemovePrefix(t *testing.T) {
+	r := httprouter.New()
+	r.Handlers[1] = writeHandler("one")
+
+	require.NoError(t, r.Add("GET", "/legacy/foo", 1))
+	require.NoError(t, r.Add("POST", "/legacy/{*path}", 1))
+	require.NoError(t, r.Add("GET", "/foo", 1))
+
+	require.Equal(t, 2, r.RemovePrefix("/legacy/"))
+
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/legacy/foo").Response.StatusCode())
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "POST", "/legacy/bar").Response.StatusCode())
+	require.Equal(t, "one", string(serve(r, "GET", "/foo").Response.Body()))
+}
+
 func TestRouter_HandleComplexParametrizedRouting(main *testing.T) {
 	main.Run("WhenParametrizedRouteAfterSimpleRoutes_OK", func(t *testing.T) {
 		r := httprouter.New()
@@ -742,3 +1010,356 @@ func TestRouter_HandleComplexParametrizedRouting(main *testing.T) {
 		}
 	})
 }
+
+func TestRouter_SaveMatchedRoute(main *testing.T) {
+	main.Run("Enabled", func(t *testing.T) {
+		r := httprouter.New()
+		r.SaveMatchedRoute = true
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {}
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		ctx := serve(r, "GET", "/users/123")
+		require.Equal(t, &radix.Route{Pattern: "/users/{id}", Key: 10}, httprouter.MatchedRoute(ctx))
+	})
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := httprouter.New()
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {}
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		ctx := serve(r, "GET", "/users/123")
+		require.Nil(t, httprouter.MatchedRoute(ctx))
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		r := httprouter.New()
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {}
+
+		require.NoError(t, r.Add("GET", "/users", 10))
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.URI().SetPath("/users")
+
+		disabled := testing.AllocsPerRun(100, func() {
+			ctx.ResetUserValues()
+			r.Handle(ctx)
+		})
+
+		r.SaveMatchedRoute = true
+		enabled := testing.AllocsPerRun(100, func() {
+			ctx.ResetUserValues()
+			r.Handle(ctx)
+		})
+
+		require.Equal(t, disabled, enabled)
+	})
+}
+
+func TestRouter_CopyParams(main *testing.T) {
+	// handle serves requests on the same ctx like fasthttp does and returns the id values seen by the handler.
+	handle := func(r *httprouter.Router, paths ...string) [][]byte {
+		var ids [][]byte
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			ids = append(ids, ctx.UserValue("id").([]byte))
+		}
+
+		ctx := &fasthttp.RequestCtx{}
+		for _, path := range paths {
+			ctx.Request.Reset()
+			ctx.ResetUserValues()
+			ctx.Request.Header.SetMethod("GET")
+			ctx.Request.URI().SetPath(path)
+			r.Handle(ctx)
+		}
+
+		return ids
+	}
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := httprouter.New()
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		var kept []string
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			kept = append(kept, string(ctx.UserValue("id").([]byte)))
+		}
+
+		ctx := &fasthttp.RequestCtx{}
+		for _, path := range []string{"/users/123", "/users/456"} {
+			ctx.Request.Reset()
+			ctx.Request.Header.SetMethod("GET")
+			ctx.Request.URI().SetPath(path)
+			r.Handle(ctx)
+		}
+
+		// values copied by the handler stay correct
+		require.Equal(t, []string{"123", "456"}, kept)
+
+		// values kept as is reference the reused path buffer
+		ids := handle(r, "/users/123", "/users/456")
+		require.Equal(t, "456", string(ids[0]))
+		require.Equal(t, "456", string(ids[1]))
+	})
+
+	main.Run("Enabled", func(t *testing.T) {
+		r := httprouter.New()
+		r.CopyParams = true
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		ids := handle(r, "/users/123", "/users/456")
+		require.Equal(t, "123", string(ids[0]))
+		require.Equal(t, "456", string(ids[1]))
+	})
+}
+
+func TestRouter_PooledParams(main *testing.T) {
+	main.Run("Params", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		require.NoError(t, r.Add("GET", "/users/{id}/files/{*path}", 10))
+
+		var ps *httprouter.RequestParams
+		var id, path string
+		var key uint64
+		var pattern string
+		var params httprouter.Params
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			ps = httprouter.ParamsFromCtx(ctx)
+			id, path = ps.Get("id"), ps.Get("*path")
+			key, pattern = ps.Key(), ps.Pattern()
+			params = append(params, ps.Params()...)
+		}
+
+		ctx := serve(r, "GET", "/users/123/files/a/b.txt")
+		require.Equal(t, "123", id)
+		require.Equal(t, "a/b.txt", path)
+		require.Equal(t, uint64(10), key)
+		require.Equal(t, "/users/{id}/files/{*path}", pattern)
+		require.Equal(t, httprouter.Params{{Key: "id", Value: "123"}, {Key: "*path", Value: "a/b.txt"}}, params)
+
+		n := 0
+		ctx.VisitUserValues(func(key []byte, _ interface{}) {
+			require.Equal(t, httprouter.ParamsUserValue, string(key))
+			n++
+		})
+		require.Equal(t, 1, n)
+
+		// fasthttp releases the params with the user values
+		ctx.ResetUserValues()
+		require.Nil(t, httprouter.ParamsFromCtx(ctx))
+		require.Empty(t, ps.Params())
+		require.Zero(t, ps.Key())
+	})
+
+	main.Run("NotFound", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		ctx := serve(r, "GET", "/orders/1")
+		require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+		require.Nil(t, httprouter.ParamsFromCtx(ctx))
+	})
+
+	main.Run("Handlers", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", func(ctx *fasthttp.RequestCtx) {
+			ctx.SetBodyString(httprouter.ParamsFromCtx(ctx).Get("id"))
+		}))
+
+		require.Equal(t, "123", string(serve(r, "GET", "/users/123").Response.Body()))
+	})
+
+	main.Run("CopyParams", func(t *testing.T) {
+		r := httprouter.New()
+		r.PooledParams = true
+		r.CopyParams = true
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		var ids []string
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			ids = append(ids, httprouter.ParamsFromCtx(ctx).Get("id"))
+		}
+
+		ctx := &fasthttp.RequestCtx{}
+		for _, path := range []string{"/users/123", "/users/456"} {
+			ctx.Request.Reset()
+			ctx.ResetUserValues()
+			ctx.Request.Header.SetMethod("GET")
+			ctx.Request.URI().SetPath(path)
+			r.Handle(ctx)
+		}
+
+		require.Equal(t, []string{"123", "456"}, ids)
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		if raceEnabled {
+			t.Skip("sync.Pool drops items randomly under the race detector")
+		}
+
+		r := httprouter.New()
+		r.PooledParams = true
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {}
+		require.NoError(t, r.Add("GET", "/users/{id}/orders/{order}/files/{*path}", 1000))
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.URI().SetPath("/users/123/orders/456/files/a/b.txt")
+
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			ctx.ResetUserValues()
+			r.Handle(ctx)
+		}))
+	})
+}
+
+func TestRouter_ProfileLabels(main *testing.T) {
+	goroutines := func() string {
+		var buf bytes.Buffer
+		_ = pprof.Lookup("goroutine").WriteTo(&buf, 1)
+		return buf.String()
+	}
+
+	main.Run("Enabled", func(t *testing.T) {
+		r := httprouter.New()
+		r.ProfileLabels = true
+
+		var profile string
+		require.NoError(t, r.RegisterHandler("POST", "/users/{id}", func(ctx *fasthttp.RequestCtx) {
+			profile = goroutines()
+		}))
+
+		serve(r, "POST", "/users/123")
+		require.Contains(t, profile, `"route":"/users/{id}"`)
+		require.Contains(t, profile, `"method":"POST"`)
+
+		// labels are removed once the handler returns
+		require.NotContains(t, goroutines(), `"route":"/users/{id}"`)
+	})
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := httprouter.New()
+
+		var profile string
+		require.NoError(t, r.RegisterHandler("POST", "/users/{id}", func(ctx *fasthttp.RequestCtx) {
+			profile = goroutines()
+		}))
+
+		serve(r, "POST", "/users/123")
+		require.NotEmpty(t, profile)
+		require.NotContains(t, profile, `"route":"/users/{id}"`)
+	})
+}
+
+func TestRouter_PanicHandler(main *testing.T) {
+	main.Run("Handler", func(t *testing.T) {
+		r := httprouter.New()
+
+		var info httprouter.PanicInfo
+		r.PanicHandler = func(ctx *fasthttp.RequestCtx, actInfo httprouter.PanicInfo) {
+			info = actInfo
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+		r.Handlers[10] = func(ctx *fasthttp.RequestCtx) {
+			panic("oops")
+		}
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		ctx := serve(r, "GET", "/users/123")
+		require.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+		require.Equal(t, "oops", info.Value)
+		require.Contains(t, string(info.Stack), "panic")
+		require.Equal(t, "/users/{id}", info.Pattern)
+		require.Equal(t, uint64(10), info.Key)
+	})
+
+	main.Run("GlobalHandler", func(t *testing.T) {
+		r := httprouter.New()
+
+		var info httprouter.PanicInfo
+		r.PanicHandler = func(ctx *fasthttp.RequestCtx, actInfo httprouter.PanicInfo) {
+			info = actInfo
+		}
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			panic("oops")
+		}
+		require.NoError(t, r.Add("GET", "/foo", 10))
+
+		serve(r, "GET", "/foo")
+		require.Equal(t, "oops", info.Value)
+		require.Equal(t, "/foo", info.Pattern)
+		require.Equal(t, uint64(10), info.Key)
+	})
+
+	main.Run("NotFoundHandlers", func(t *testing.T) {
+		r := httprouter.New()
+
+		var info httprouter.PanicInfo
+		r.PanicHandler = func(ctx *fasthttp.RequestCtx, actInfo httprouter.PanicInfo) {
+			info = actInfo
+		}
+		r.PageNotFoundHandler = func(ctx *fasthttp.RequestCtx) {
+			panic("not found")
+		}
+		r.MethodNotAllowedHandler = func(ctx *fasthttp.RequestCtx) {
+			panic("method not allowed")
+		}
+
+		serve(r, "GET", "/foo")
+		require.Equal(t, "not found", info.Value)
+		require.Equal(t, "", info.Pattern)
+		require.Equal(t, uint64(0), info.Key)
+
+		serve(r, "UNSUPPORTED", "/foo")
+		require.Equal(t, "method not allowed", info.Value)
+	})
+
+	main.Run("NoPanicHandler", func(t *testing.T) {
+		r := httprouter.New()
+		r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {
+			panic("oops")
+		}
+		require.NoError(t, r.Add("GET", "/foo", 10))
+
+		require.PanicsWithValue(t, "oops", func() {
+			serve(r, "GET", "/foo")
+		})
+	})
+}
+
+func TestRouter_ColonSyntax(t *testing.T) {
+	r := httprouter.New()
+	r.ColonSyntax = true
+	r.GlobalHandler = func(ctx *fasthttp.RequestCtx) {}
+
+	require.NoError(t, r.Add("GET", "/users/:id", 1))
+	require.NoError(t, r.Add("GET", "/static/*filepath", 2))
+
+	ctx := serve(r, "GET", "/users/123")
+	require.Equal(t, []byte("123"), ctx.UserValue("id"))
+
+	ctx = serve(r, "GET", "/static/js/app.js")
+	require.Equal(t, []byte("/js/app.js"), ctx.UserValue("*filepath"))
+	require.Nil(t, ctx.UserValue("filepath"))
+
+	var in struct {
+		Filepath string `path:"filepath"`
+	}
+	require.NoError(t, httprouter.BindCtx(ctx, &in))
+	require.Equal(t, "/js/app.js", in.Filepath)
+
+	ctx = serve(r, "GET", "/static/")
+	require.Equal(t, []byte("/"), ctx.UserValue("*filepath"))
+
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/static").Response.StatusCode())
+
+	require.NoError(t, r.Remove("GET", "/users/:id"))
+	require.Equal(t, fasthttp.StatusNotFound, serve(r, "GET", "/users/123").Response.StatusCode())
+
+	require.EqualError(t, r.Add("GET", "/static/*path/foo", 3), "convert: wildcard must be at the end: /static/*path/foo")
+}
@@ -0,0 +1,25 @@
+package stdrouter
+
+import (
+	"github.com/makasim/httprouter/internal/param"
+)
+
+// Bind sets fields of the struct dst points to from params. A field is bound to the param named by its path tag:
+//
+//	type getOrder struct {
+//		UserID  int64  `path:"user_id"`
+//		OrderID string `path:"id"`
+//	}
+//
+// Fields may be strings, bools, ints, uints, floats, or implement encoding.TextUnmarshaler.
+// Bind sets every field it can and returns the errors of the others joined, each one is a *ParamError.
+func Bind(ps Params, dst interface{}) error {
+	return param.Bind(ps, dst)
+}
+
+// CheckBind reports whether values of v's struct type can be bound from params of pattern:
+// every path tagged field must have a supported type and name a param of pattern.
+// Call it when registering a route which handler uses Bind to fail early instead of on every request.
+func CheckBind(pattern string, v interface{}) error {
+	return param.CheckBind(pattern, v)
+}
@@ -0,0 +1,74 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+// the binder is tested in internal/param, these tests cover the router side
+
+func TestBind(main *testing.T) {
+	main.Run("Handler", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.RegisterHandler("GET", "/users/{user_id}/orders/{id}", stdrouter.HandlerFunc(
+			func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+				var in struct {
+					UserID int    `path:"user_id"`
+					ID     string `path:"id"`
+				}
+				if err := stdrouter.Bind(ps, &in); err != nil {
+					r.HandleParamError(rw, req, err)
+					return
+				}
+
+				_, _ = rw.Write([]byte(strconv.Itoa(in.UserID) + ":" + in.ID))
+			})))
+
+		require.Equal(t, "1:abc", serve(r, "GET", "/users/1/orders/abc").Body.String())
+
+		rw := serve(r, "GET", "/users/x/orders/abc")
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Equal(t, "param \"user_id\": invalid int \"x\": invalid syntax\n", rw.Body.String())
+	})
+
+	main.Run("Errors", func(t *testing.T) {
+		var in struct {
+			ID int `path:"id"`
+		}
+
+		err := stdrouter.Bind(stdrouter.Params{}, &in)
+		require.ErrorIs(t, err, stdrouter.ErrParamMissing)
+
+		var paramErr *stdrouter.ParamError
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, "id", paramErr.Name)
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		type req struct {
+			UserID int64  `path:"user_id"`
+			ID     string `path:"id"`
+		}
+		ps := stdrouter.Params{{Key: "user_id", Value: "42"}, {Key: "id", Value: "abc"}}
+
+		var in req
+		require.NoError(t, stdrouter.Bind(ps, &in))
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			_ = stdrouter.Bind(ps, &in)
+		}))
+	})
+}
+
+func TestCheckBind(t *testing.T) {
+	type req struct {
+		UserID  int    `path:"user_id"`
+		OrderID string `path:"id"`
+	}
+
+	require.NoError(t, stdrouter.CheckBind("/users/{user_id}/orders/{id}", req{}))
+	require.EqualError(t, stdrouter.CheckBind("/users/{user_id}", &req{}), `bind stdrouter_test.req: field OrderID: param "id" not in pattern "/users/{user_id}"`)
+}
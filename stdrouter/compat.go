@@ -0,0 +1,49 @@
+package stdrouter
+
+import (
+	"net/http"
+)
+
+// Handle is a julienschmidt/httprouter compatible handle function.
+// Set Router.ColonSyntax to register routes with julienschmidt/httprouter paths like /users/:id.
+type Handle = HandlerFunc
+
+// ByName returns the value of the first param with the given name, same as Get.
+func (ps Params) ByName(name string) string {
+	return ps.Get(name)
+}
+
+// GET is a shortcut for RegisterHandler(http.MethodGet, path, handle).
+func (r *Router) GET(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodGet, path, handle)
+}
+
+// HEAD is a shortcut for RegisterHandler(http.MethodHead, path, handle).
+func (r *Router) HEAD(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodHead, path, handle)
+}
+
+// OPTIONS is a shortcut for RegisterHandler(http.MethodOptions, path, handle).
+func (r *Router) OPTIONS(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodOptions, path, handle)
+}
+
+// POST is a shortcut for RegisterHandler(http.MethodPost, path, handle).
+func (r *Router) POST(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodPost, path, handle)
+}
+
+// PUT is a shortcut for RegisterHandler(http.MethodPut, path, handle).
+func (r *Router) PUT(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodPut, path, handle)
+}
+
+// PATCH is a shortcut for RegisterHandler(http.MethodPatch, path, handle).
+func (r *Router) PATCH(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodPatch, path, handle)
+}
+
+// DELETE is a shortcut for RegisterHandler(http.MethodDelete, path, handle).
+func (r *Router) DELETE(path string, handle Handle) error {
+	return r.RegisterHandler(http.MethodDelete, path, handle)
+}
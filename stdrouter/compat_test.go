@@ -0,0 +1,70 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_ColonSyntax(main *testing.T) {
+	main.Run("Shortcuts", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ColonSyntax = true
+
+		handle := func(name string) stdrouter.Handle {
+			return func(rw http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				_, _ = rw.Write([]byte(ps.ByName(name)))
+			}
+		}
+
+		require.NoError(t, r.GET("/users/:id", handle("id")))
+		require.NoError(t, r.HEAD("/users/:id", handle("id")))
+		require.NoError(t, r.OPTIONS("/users/:id", handle("id")))
+		require.NoError(t, r.POST("/users/:id", handle("id")))
+		require.NoError(t, r.PUT("/users/:id", handle("id")))
+		require.NoError(t, r.PATCH("/users/:id", handle("id")))
+		require.NoError(t, r.DELETE("/users/:id", handle("id")))
+		require.NoError(t, r.GET("/static/*filepath", handle("filepath")))
+
+		for _, method := range []string{"GET", "HEAD", "OPTIONS", "POST", "PUT", "PATCH", "DELETE"} {
+			require.Equal(t, "123", serve(r, method, "/users/123").Body.String(), method)
+		}
+		// same as julienschmidt/httprouter, the catch-all value keeps the leading slash and the bare prefix matches
+		require.Equal(t, "/js/app.js", serve(r, "GET", "/static/js/app.js").Body.String())
+		require.Equal(t, "/", serve(r, "GET", "/static/").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/static").Result().StatusCode)
+
+		require.NoError(t, r.Remove("GET", "/users/:id"))
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/users/123").Result().StatusCode)
+	})
+
+	main.Run("Group", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ColonSyntax = true
+
+		require.NoError(t, r.Group("/api").RegisterHandler("GET", "/users/:id", stdrouter.Handle(
+			func(rw http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				_, _ = rw.Write([]byte(ps.ByName("id")))
+			})))
+
+		require.Equal(t, "123", serve(r, "GET", "/api/users/123").Body.String())
+	})
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.NoError(t, r.GET("/users/:id", func(rw http.ResponseWriter, _ *http.Request, _ stdrouter.Params) {}))
+
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/users/123").Result().StatusCode)
+		require.Equal(t, http.StatusOK, serve(r, "GET", "/users/:id").Result().StatusCode)
+	})
+
+	main.Run("Invalid", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ColonSyntax = true
+
+		require.EqualError(t, r.Add("GET", "/users/:", 1), "convert: empty param name at 7: /users/:")
+	})
+}
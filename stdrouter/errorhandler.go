@@ -0,0 +1,97 @@
+package stdrouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/makasim/httprouter/radix"
+)
+
+// ErrorHandlerFunc is a handler that returns an error instead of writing an error response.
+// Register it with RegisterErrorHandler.
+type ErrorHandlerFunc func(http.ResponseWriter, *http.Request, Params) error
+
+// RegisterErrorHandler works like RegisterHandler for a handler that returns an error.
+// A returned error is passed to ErrorHandler along with the route ServeHTTP matched,
+// which differs from method and path when the handler id is registered for other routes too.
+// Without ErrorHandler a *ParamError goes to HandleParamError, any other error gets 500 Internal Server Error.
+func (r *Router) RegisterErrorHandler(method, path string, handler ErrorHandlerFunc, middleware ...Middleware) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+
+	pattern, err := r.convertPath(path)
+	if err != nil {
+		return err
+	}
+
+	var hID HandlerID
+	hID = r.AddHandler(HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps Params) {
+		if err := handler(rw, req, ps); err != nil {
+			route, ok := r.matchedRoute(req)
+			if !ok {
+				// the handler is called outside of ServeHTTP, report the route it was registered for
+				route = RouteInfo{Method: method, Pattern: pattern, HandlerID: hID, Meta: r.meta[hID]}
+			}
+
+			r.handleError(rw, req, route, err)
+		}
+	}), middleware...)
+
+	if err := r.add("", method, pattern, hID); err != nil {
+		_ = r.RemoveHandler(hID)
+		return err
+	}
+
+	return nil
+}
+
+// matchedRoute returns the route ServeHTTP matched for req. It is found by http.Request.Pattern
+// when SaveMatchedRoute is enabled, otherwise the request is searched again.
+func (r *Router) matchedRoute(req *http.Request) (RouteInfo, bool) {
+	if r.SaveMatchedRoute {
+		return r.MatchedRoute(req)
+	}
+
+	methodIndex := methodIndexOf(req.Method)
+	if methodIndex == -1 {
+		return RouteInfo{}, false
+	}
+
+	c := capturesPool.Get().(*radix.Captures)
+	defer capturesPool.Put(c)
+
+	if len(r.hosts) > 0 {
+		host := hostOf(req.Host)
+		if trees, ok := r.hosts[host]; ok {
+			if route := r.searchTrees(trees, methodIndex, req.URL.Path, c); route != nil {
+				info, ok := r.findRoute(trees, methodIndex, route.Pattern)
+				info.Host = host
+				return info, ok
+			}
+		}
+	}
+
+	route := r.searchTrees(r.Trees, methodIndex, req.URL.Path, c)
+	if route == nil {
+		return RouteInfo{}, false
+	}
+
+	return r.findRoute(r.Trees, methodIndex, route.Pattern)
+}
+
+func (r *Router) handleError(rw http.ResponseWriter, req *http.Request, route RouteInfo, err error) {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(rw, req, route, err)
+		return
+	}
+
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		r.HandleParamError(rw, req, err)
+		return
+	}
+
+	http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,124 @@
+package stdrouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_RegisterErrorHandler(main *testing.T) {
+	getUser := func(rw http.ResponseWriter, _ *http.Request, ps stdrouter.Params) error {
+		id, err := ps.Int("id")
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return fmt.Errorf("user %d not found", id)
+		}
+
+		_, _ = rw.Write([]byte(ps.Get("id")))
+		return nil
+	}
+
+	main.Run("Default", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+
+		rec := serve(r, "GET", "/users/1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "1", rec.Body.String())
+
+		rec = serve(r, "GET", "/users/abc")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, "param \"id\": invalid int \"abc\": invalid syntax\n", rec.Body.String())
+
+		rec = serve(r, "GET", "/users/0")
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Equal(t, "Internal Server Error\n", rec.Body.String())
+	})
+
+	main.Run("ErrorHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ColonSyntax = true
+
+		var route stdrouter.RouteInfo
+		r.ErrorHandler = func(rw http.ResponseWriter, _ *http.Request, ri stdrouter.RouteInfo, err error) {
+			route = ri
+
+			rw.Header().Set("Content-Type", "application/problem+json")
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"detail":"` + err.Error() + `"}`))
+		}
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/:id", getUser))
+		r.SetMeta(1, stdrouter.Meta{Name: "get-user"})
+
+		rec := serve(r, "GET", "/users/0")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		require.Equal(t, `{"detail":"user 0 not found"}`, rec.Body.String())
+		require.Equal(t, stdrouter.RouteInfo{
+			Method:    "GET",
+			Pattern:   "/users/{id}",
+			HandlerID: 1,
+			Meta:      stdrouter.Meta{Name: "get-user"},
+		}, route)
+	})
+
+	main.Run("Group", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var route stdrouter.RouteInfo
+		r.ErrorHandler = func(rw http.ResponseWriter, _ *http.Request, ri stdrouter.RouteInfo, err error) {
+			route = ri
+			_, _ = rw.Write([]byte(err.Error()))
+		}
+
+		g := r.Group("/api", writeMiddleware("api:"))
+		require.NoError(t, g.RegisterErrorHandler("GET", "/users/{id}", getUser))
+
+		rec := serve(r, "GET", "/api/users/0")
+		require.Equal(t, "api:user 0 not found", rec.Body.String())
+		require.Equal(t, "/api/users/{id}", route.Pattern)
+	})
+
+	main.Run("MatchedRoute", func(t *testing.T) {
+		for _, save := range []bool{false, true} {
+			r := stdrouter.New()
+			r.SaveMatchedRoute = save
+
+			var route stdrouter.RouteInfo
+			r.ErrorHandler = func(_ http.ResponseWriter, _ *http.Request, ri stdrouter.RouteInfo, _ error) {
+				route = ri
+			}
+			require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", func(http.ResponseWriter, *http.Request, stdrouter.Params) error {
+				return fmt.Errorf("failed")
+			}))
+			r.SetMeta(1, stdrouter.Meta{Name: "user"})
+			require.NoError(t, r.Add(stdrouter.MethodAny, "/accounts/{id}", 1))
+
+			serve(r, "POST", "/accounts/1")
+			require.Equal(t, stdrouter.RouteInfo{
+				Method:    stdrouter.MethodAny,
+				Pattern:   "/accounts/{id}",
+				HandlerID: 1,
+				Meta:      stdrouter.Meta{Name: "user"},
+			}, route, save)
+
+			serve(r, "GET", "/users/1")
+			require.Equal(t, "GET", route.Method, save)
+			require.Equal(t, "/users/{id}", route.Pattern, save)
+		}
+	})
+
+	main.Run("Invalid", func(t *testing.T) {
+		r := stdrouter.New()
+		require.EqualError(t, r.RegisterErrorHandler("GET", "/users/{id}", nil), "handler is nil")
+
+		require.NoError(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+		require.Error(t, r.RegisterErrorHandler("GET", "/users/{id}", getUser))
+		require.Len(t, r.Routes(), 1)
+	})
+}
@@ -0,0 +1,89 @@
+package stdrouter
+
+import (
+	"strings"
+)
+
+// Group registers routes under a shared path prefix and wraps their handlers with shared middleware.
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a route group with the given prefix.
+// Middleware is applied in the order given, the first one being the outermost.
+func (r *Router) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     r,
+		prefix:     prefix,
+		middleware: middleware,
+	}
+}
+
+// Group creates a nested group. The prefix is joined to the parent prefix,
+// the parent middleware wraps the nested group middleware.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     joinPath(g.prefix, prefix),
+		middleware: g.chain(middleware),
+	}
+}
+
+// Prefix returns the full path prefix of the group.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// RegisterHandler adds handler to the router wrapped with the group middleware
+// followed by the given one, and registers a route for method and the prefixed path.
+func (g *Group) RegisterHandler(method, path string, handler Handler, middleware ...Middleware) error {
+	return g.router.RegisterHandler(method, joinPath(g.prefix, path), handler, g.chain(middleware)...)
+}
+
+// RegisterHandlerWithMeta works like RegisterHandler and attaches meta to the handler.
+func (g *Group) RegisterHandlerWithMeta(method, path string, handler Handler, meta Meta, middleware ...Middleware) error {
+	return g.router.RegisterHandlerWithMeta(method, joinPath(g.prefix, path), handler, meta, g.chain(middleware)...)
+}
+
+// RegisterErrorHandler works like RegisterHandler for a handler that returns an error, see Router.RegisterErrorHandler.
+func (g *Group) RegisterErrorHandler(method, path string, handler ErrorHandlerFunc, middleware ...Middleware) error {
+	return g.router.RegisterErrorHandler(method, joinPath(g.prefix, path), handler, g.chain(middleware)...)
+}
+
+// Add adds a route for method and the prefixed path. The handler is not wrapped with the group middleware.
+func (g *Group) Add(method, path string, handlerID HandlerID) error {
+	return g.router.Add(method, joinPath(g.prefix, path), handlerID)
+}
+
+// Remove removes a route for method and the prefixed path.
+func (g *Group) Remove(method, path string) error {
+	return g.router.Remove(method, joinPath(g.prefix, path))
+}
+
+func (g *Group) chain(middleware []Middleware) []Middleware {
+	mw := make([]Middleware, 0, len(g.middleware)+len(middleware))
+	mw = append(mw, g.middleware...)
+	mw = append(mw, middleware...)
+
+	return mw
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+
+	if strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, "/") {
+		return prefix + path[1:]
+	}
+	if !strings.HasSuffix(prefix, "/") && !strings.HasPrefix(path, "/") {
+		return prefix + "/" + path
+	}
+
+	return prefix + path
+}
@@ -0,0 +1,116 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(main *testing.T) {
+	main.Run("Prefix", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.Equal(t, "/api", r.Group("/api").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api").Group("/v1").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api/").Group("/v1").Prefix())
+		require.Equal(t, "/api/v1", r.Group("/api").Group("v1").Prefix())
+	})
+
+	main.Run("RegisterHandler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		api := r.Group("/api")
+		v1 := api.Group("/v1")
+
+		require.NoError(t, api.RegisterHandler("GET", "/status", writeHandler("status")))
+		require.NoError(t, v1.RegisterHandler("GET", "/users/{id}", writeHandler("user")))
+
+		rw := serve(r, "GET", "/api/status")
+		require.Equal(t, "status", rw.Body.String())
+
+		rw = serve(r, "GET", "/api/v1/users/123")
+		require.Equal(t, "user", rw.Body.String())
+
+		rw = serve(r, "GET", "/users/123")
+		require.Equal(t, http.StatusNotFound, rw.Result().StatusCode)
+	})
+
+	main.Run("MiddlewareOrder", func(t *testing.T) {
+		r := stdrouter.New()
+
+		api := r.Group("/api", writeMiddleware("a"), writeMiddleware("b"))
+		v1 := api.Group("/v1", writeMiddleware("c"))
+
+		require.NoError(t, api.RegisterHandler("GET", "/status", writeHandler("h")))
+		require.NoError(t, v1.RegisterHandler("GET", "/status", writeHandler("h")))
+
+		rw := serve(r, "GET", "/api/status")
+		require.Equal(t, "abh", rw.Body.String())
+
+		rw = serve(r, "GET", "/api/v1/status")
+		require.Equal(t, "abch", rw.Body.String())
+	})
+
+	main.Run("SiblingGroupsDoNotShareMiddleware", func(t *testing.T) {
+		r := stdrouter.New()
+
+		api := r.Group("/api", writeMiddleware("a"))
+		v1 := api.Group("/v1", writeMiddleware("1"))
+		v2 := api.Group("/v2", writeMiddleware("2"))
+
+		require.NoError(t, v1.RegisterHandler("GET", "/status", writeHandler("h")))
+		require.NoError(t, v2.RegisterHandler("GET", "/status", writeHandler("h")))
+
+		require.Equal(t, "a1h", serve(r, "GET", "/api/v1/status").Body.String())
+		require.Equal(t, "a2h", serve(r, "GET", "/api/v2/status").Body.String())
+	})
+
+	main.Run("Remove", func(t *testing.T) {
+		r := stdrouter.New()
+
+		api := r.Group("/api")
+		require.NoError(t, api.RegisterHandler("GET", "/foo", writeHandler("foo")))
+		require.NoError(t, api.RegisterHandler("GET", "/bar", writeHandler("bar")))
+
+		require.NoError(t, api.Remove("GET", "/foo"))
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/api/foo").Result().StatusCode)
+		require.Equal(t, "bar", serve(r, "GET", "/api/bar").Body.String())
+
+		require.NoError(t, r.Remove("GET", "/api/bar"))
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/api/bar").Result().StatusCode)
+	})
+
+	main.Run("Add", func(t *testing.T) {
+		r := stdrouter.New()
+		hID := r.AddHandler(writeHandler("foo"))
+
+		require.NoError(t, r.Group("/api").Add("GET", "/foo", hID))
+		require.Equal(t, "foo", serve(r, "GET", "/api/foo").Body.String())
+	})
+}
+
+func writeHandler(body string) stdrouter.Handler {
+	return stdrouter.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request, _ stdrouter.Params) {
+		_, _ = rw.Write([]byte(body))
+	})
+}
+
+func writeMiddleware(body string) stdrouter.Middleware {
+	return func(next stdrouter.Handler) stdrouter.Handler {
+		return stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+			_, _ = rw.Write([]byte(body))
+			next.ServeHTTP(rw, req, ps)
+		})
+	}
+}
+
+func serve(r http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, http.NoBody)
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+
+	return rw
+}
@@ -0,0 +1,107 @@
+package stdrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+var ErrInvalidBody = fmt.Errorf("invalid request body")
+
+// JSONFunc handles a request decoded by a handler registered with JSON.
+type JSONFunc[Req, Resp any] func(ctx context.Context, req Req, ps Params) (Resp, error)
+
+// JSON registers a handler for method and path which decodes the JSON request body into Req,
+// binds path params into it with Bind, calls fn and encodes the returned Resp as JSON with status 200.
+// Path params override body fields tagged with the same path tag. A request without a body is not decoded.
+//
+// Errors are written by Router.JSONErrorEncoder, or EncodeJSONError if it is not set.
+// A Req struct is checked with CheckBind, so a path tag that names no param of path fails the registration.
+func JSON[Req, Resp any](r *Router, method, path string, fn JSONFunc[Req, Resp], middleware ...Middleware) error {
+	bind := reflect.TypeOf((*Req)(nil)).Elem().Kind() == reflect.Struct
+	if bind {
+		pattern, err := r.convertPath(path)
+		if err != nil {
+			return err
+		}
+
+		var req Req
+		if err := CheckBind(pattern, &req); err != nil {
+			return err
+		}
+	}
+
+	return r.RegisterHandler(method, path, HandlerFunc(func(rw http.ResponseWriter, httpReq *http.Request, ps Params) {
+		var req Req
+		if err := decodeJSON(httpReq, &req); err != nil {
+			r.encodeJSONError(rw, httpReq, err)
+			return
+		}
+		if bind {
+			if err := Bind(ps, &req); err != nil {
+				r.encodeJSONError(rw, httpReq, err)
+				return
+			}
+		}
+
+		resp, err := fn(httpReq.Context(), req, ps)
+		if err != nil {
+			r.encodeJSONError(rw, httpReq, err)
+			return
+		}
+
+		writeJSON(rw, http.StatusOK, resp)
+	}), middleware...)
+}
+
+func decodeJSON(req *http.Request, v interface{}) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %v", ErrInvalidBody, err)
+	}
+
+	return nil
+}
+
+func writeJSON(rw http.ResponseWriter, code int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func (r *Router) encodeJSONError(rw http.ResponseWriter, req *http.Request, err error) {
+	if r.JSONErrorEncoder != nil {
+		r.JSONErrorEncoder(rw, req, err)
+		return
+	}
+
+	EncodeJSONError(rw, req, err)
+}
+
+// EncodeJSONError writes err as a {"error": "..."} JSON object.
+// Param and body errors get 400 Bad Request, an error with a StatusCode() int method in its chain gets that status,
+// any other error gets 500 Internal Server Error and its text is not exposed.
+func EncodeJSONError(rw http.ResponseWriter, _ *http.Request, err error) {
+	code := http.StatusInternalServerError
+	msg := http.StatusText(code)
+
+	var paramErr *ParamError
+	var statusErr interface{ StatusCode() int }
+	switch {
+	case errors.As(err, &paramErr), errors.Is(err, ErrInvalidBody):
+		code, msg = http.StatusBadRequest, err.Error()
+	case errors.As(err, &statusErr):
+		code, msg = statusErr.StatusCode(), err.Error()
+	}
+
+	writeJSON(rw, code, struct {
+		Error string `json:"error"`
+	}{Error: msg})
+}
@@ -0,0 +1,150 @@
+package stdrouter_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+type updateOrderReq struct {
+	ID     int    `path:"id" json:"-"`
+	Status string `json:"status"`
+}
+
+type updateOrderResp struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("status %d", e.code)
+}
+
+func (e statusError) StatusCode() int {
+	return e.code
+}
+
+func serveJSON(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, http.NoBody)
+	if body != "" {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestJSON(main *testing.T) {
+	updateOrder := func(_ context.Context, req updateOrderReq, _ stdrouter.Params) (updateOrderResp, error) {
+		switch req.Status {
+		case "forbidden":
+			return updateOrderResp{}, fmt.Errorf("update: %w", statusError{code: http.StatusForbidden})
+		case "fail":
+			return updateOrderResp{}, fmt.Errorf("database is down")
+		}
+
+		return updateOrderResp{ID: req.ID, Status: req.Status}, nil
+	}
+
+	main.Run("OK", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, stdrouter.JSON(r, "POST", "/orders/{id}", updateOrder))
+
+		rec := serveJSON(r, "POST", "/orders/12", `{"status":"paid"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"id":12,"status":"paid"}`, rec.Body.String())
+	})
+
+	main.Run("NoBody", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, stdrouter.JSON(r, "GET", "/orders/{id}", updateOrder))
+
+		rec := serveJSON(r, "GET", "/orders/12", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"id":12,"status":""}`, rec.Body.String())
+	})
+
+	main.Run("NonStruct", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, stdrouter.JSON(r, "POST", "/sum", func(_ context.Context, req []int, _ stdrouter.Params) (int, error) {
+			sum := 0
+			for _, v := range req {
+				sum += v
+			}
+			return sum, nil
+		}))
+
+		rec := serveJSON(r, "POST", "/sum", `[1,2,3]`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "6\n", rec.Body.String())
+	})
+
+	main.Run("Errors", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, stdrouter.JSON(r, "POST", "/orders/{id}", updateOrder))
+
+		rec := serveJSON(r, "POST", "/orders/12", `{"status":`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.JSONEq(t, `{"error":"invalid request body: unexpected EOF"}`, rec.Body.String())
+
+		rec = serveJSON(r, "POST", "/orders/abc", `{"status":"paid"}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.JSONEq(t, `{"error":"param \"id\": invalid int \"abc\": invalid syntax"}`, rec.Body.String())
+
+		rec = serveJSON(r, "POST", "/orders/12", `{"status":"forbidden"}`)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+		require.JSONEq(t, `{"error":"update: status 403"}`, rec.Body.String())
+
+		rec = serveJSON(r, "POST", "/orders/12", `{"status":"fail"}`)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.JSONEq(t, `{"error":"Internal Server Error"}`, rec.Body.String())
+	})
+
+	main.Run("JSONErrorEncoder", func(t *testing.T) {
+		r := stdrouter.New()
+		r.JSONErrorEncoder = func(rw http.ResponseWriter, req *http.Request, err error) {
+			var paramErr *stdrouter.ParamError
+			require.ErrorAs(t, err, &paramErr)
+
+			rw.Header().Set("Content-Type", "application/problem+json")
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = rw.Write([]byte(`{"title":"invalid param","param":"` + paramErr.Name + `"}`))
+		}
+		require.NoError(t, stdrouter.JSON(r, "POST", "/orders/{id}", updateOrder))
+
+		rec := serveJSON(r, "POST", "/orders/abc", "")
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"title":"invalid param","param":"id"}`, rec.Body.String())
+	})
+
+	main.Run("CheckBind", func(t *testing.T) {
+		r := stdrouter.New()
+
+		err := stdrouter.JSON(r, "POST", "/orders/{order_id}", updateOrder)
+		require.EqualError(t, err, `bind stdrouter_test.updateOrderReq: field ID: param "id" not in pattern "/orders/{order_id}"`)
+		require.Empty(t, r.Routes())
+	})
+
+	main.Run("ColonSyntax", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ColonSyntax = true
+		require.NoError(t, stdrouter.JSON(r, "POST", "/orders/:id", updateOrder))
+
+		rec := serveJSON(r, "POST", "/orders/12", `{"status":"paid"}`)
+		require.JSONEq(t, `{"id":12,"status":"paid"}`, rec.Body.String())
+	})
+}
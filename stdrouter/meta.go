@@ -0,0 +1,104 @@
+package stdrouter
+
+import (
+	"sort"
+
+	"github.com/makasim/httprouter/radix"
+)
+
+// Meta describes a handler and the routes registered for it.
+type Meta struct {
+	Name string
+	Tags []string
+	// Values holds arbitrary metadata such as the owning team, auth scopes or a rate-limit class.
+	Values map[string]interface{}
+}
+
+// Value returns the metadata value stored under key, or nil.
+func (m Meta) Value(key string) interface{} {
+	return m.Values[key]
+}
+
+// HasTag reports whether tag is among the meta tags.
+func (m Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RouteInfo describes a registered route.
+// Routes reports radix patterns, a matched route registered with Handle reports the pattern passed to Handle.
+type RouteInfo struct {
+	// Host is set for routes registered with a host specific pattern, see Handle.
+	Host      string
+	Method    string
+	Pattern   string
+	HandlerID HandlerID
+	Meta      Meta
+}
+
+// AddHandlerWithMeta works like AddHandler and attaches meta to the returned handler id.
+func (r *Router) AddHandlerWithMeta(handler Handler, meta Meta, middleware ...Middleware) HandlerID {
+	hID := r.AddHandler(handler, middleware...)
+	r.SetMeta(hID, meta)
+
+	return hID
+}
+
+// RegisterHandlerWithMeta works like RegisterHandler and attaches meta to the handler.
+func (r *Router) RegisterHandlerWithMeta(method, path string, handler Handler, meta Meta, middleware ...Middleware) error {
+	hID := r.AddHandlerWithMeta(handler, meta, middleware...)
+	return r.Add(method, path, hID)
+}
+
+// SetMeta attaches meta to the handler id. The id does not have to belong to a handler added with AddHandler,
+// so routes served by GlobalHandler can have metadata too.
+func (r *Router) SetMeta(hID HandlerID, meta Meta) {
+	r.meta[hID] = meta
+}
+
+// Meta returns metadata attached to the handler id.
+// At request time Router.MatchedRoute(req) returns the matched HandlerID together with its Meta,
+// it requires SaveMatchedRoute to be enabled.
+func (r *Router) Meta(hID HandlerID) (Meta, bool) {
+	meta, ok := r.meta[hID]
+	return meta, ok
+}
+
+// Routes returns all registered routes with their metadata.
+// Host specific routes follow the rest, ordered by host.
+func (r *Router) Routes() []RouteInfo {
+	routes := r.appendRoutes(nil, "", r.Trees)
+
+	hosts := make([]string, 0, len(r.hosts))
+	for host := range r.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		routes = r.appendRoutes(routes, host, r.hosts[host])
+	}
+
+	return routes
+}
+
+func (r *Router) appendRoutes(routes []RouteInfo, host string, trees []radix.Tree) []RouteInfo {
+	for i, tree := range trees {
+		for _, route := range tree.Routes() {
+			routes = append(routes, RouteInfo{
+				Host:      host,
+				Method:    methods[i],
+				Pattern:   route.Pattern,
+				HandlerID: HandlerID(route.Key),
+				Meta:      r.meta[HandlerID(route.Key)],
+			})
+		}
+	}
+
+	return routes
+}
@@ -0,0 +1,85 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Meta(main *testing.T) {
+	main.Run("RegisterHandlerWithMeta", func(t *testing.T) {
+		r := stdrouter.New()
+
+		meta := stdrouter.Meta{
+			Name: "get-user",
+			Tags: []string{"users"},
+			Values: map[string]interface{}{
+				"team":   "identity",
+				"scopes": []string{"users:read"},
+			},
+		}
+		require.NoError(t, r.RegisterHandlerWithMeta("GET", "/users/{id}", writeHandler("user"), meta))
+
+		routes := r.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "GET", routes[0].Method)
+		require.Equal(t, "/users/{id}", routes[0].Pattern)
+		require.Equal(t, meta, routes[0].Meta)
+
+		actMeta, ok := r.Meta(routes[0].HandlerID)
+		require.True(t, ok)
+		require.Equal(t, meta, actMeta)
+		require.Equal(t, "identity", actMeta.Value("team"))
+		require.True(t, actMeta.HasTag("users"))
+		require.False(t, actMeta.HasTag("orders"))
+	})
+
+	main.Run("RequestTime", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+
+		var actMeta stdrouter.Meta
+		r.Use(func(next stdrouter.Handler) stdrouter.Handler {
+			return stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+				route, _ := r.MatchedRoute(req)
+				actMeta = route.Meta
+				next.ServeHTTP(rw, req, ps)
+			})
+		})
+
+		g := r.Group("/api")
+		require.NoError(t, g.RegisterHandlerWithMeta("GET", "/orders", writeHandler("orders"), stdrouter.Meta{Name: "list-orders"}))
+		require.NoError(t, g.RegisterHandlerWithMeta("GET", "/users", writeHandler("users"), stdrouter.Meta{Name: "list-users"}))
+
+		require.Equal(t, "orders", serve(r, "GET", "/api/orders").Body.String())
+		require.Equal(t, "list-orders", actMeta.Name)
+
+		require.Equal(t, "users", serve(r, "GET", "/api/users").Body.String())
+		require.Equal(t, "list-users", actMeta.Name)
+	})
+
+	main.Run("GlobalHandlerRoutes", func(t *testing.T) {
+		r := stdrouter.New()
+
+		r.SetMeta(123, stdrouter.Meta{Name: "global"})
+		require.NoError(t, r.Add("GET", "/foo", 123))
+		require.NoError(t, r.Add(stdrouter.MethodAny, "/bar", 124))
+
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/foo", HandlerID: 123, Meta: stdrouter.Meta{Name: "global"}},
+			{Method: stdrouter.MethodAny, Pattern: "/bar", HandlerID: 124},
+		}, r.Routes())
+	})
+
+	main.Run("RemoveHandler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		hID := r.AddHandlerWithMeta(writeHandler("foo"), stdrouter.Meta{Name: "foo"})
+		r.RemoveHandler(hID)
+
+		_, ok := r.Meta(hID)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,81 @@
+package stdrouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware wraps a handler with additional behavior.
+type Middleware func(Handler) Handler
+
+// Use appends router-wide middleware. The first middleware is the outermost.
+//
+// Middleware is applied once, when a handler is added with AddHandler, so handlers added before Use are not wrapped.
+// GlobalHandler, PageNotFoundHandler and MethodNotAllowedHandler are always served through the whole chain,
+// the middleware wraps whatever handler the field holds when a request is served.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+
+	r.globalHandler = r.wrap(HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps Params) {
+		r.GlobalHandler.ServeHTTP(rw, req, ps)
+	}))
+	r.pageNotFoundHandler = r.wrap(HandlerFunc(func(rw http.ResponseWriter, req *http.Request, _ Params) {
+		r.PageNotFoundHandler(rw, req)
+	}))
+	r.methodNotAllowedHandler = r.wrap(HandlerFunc(func(rw http.ResponseWriter, req *http.Request, _ Params) {
+		r.MethodNotAllowedHandler(rw, req)
+	}))
+}
+
+func (r *Router) wrap(handler Handler, middleware ...Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+
+	return handler
+}
+
+func (r *Router) serveGlobal(rw http.ResponseWriter, req *http.Request, ps Params) {
+	if r.globalHandler != nil {
+		r.globalHandler.ServeHTTP(rw, req, ps)
+		return
+	}
+
+	r.GlobalHandler.ServeHTTP(rw, req, ps)
+}
+
+func (r *Router) servePageNotFound(rw http.ResponseWriter, req *http.Request) {
+	if r.pageNotFoundHandler != nil {
+		r.pageNotFoundHandler.ServeHTTP(rw, req, nil)
+		return
+	}
+
+	r.PageNotFoundHandler(rw, req)
+}
+
+func (r *Router) serveMethodNotAllowed(rw http.ResponseWriter, req *http.Request) {
+	if r.methodNotAllowedHandler != nil {
+		r.methodNotAllowedHandler.ServeHTTP(rw, req, nil)
+		return
+	}
+
+	r.MethodNotAllowedHandler(rw, req)
+}
+
+// stdHandler adapts http.Handler. Params are passed with http.Request.SetPathValue and the request context.
+func stdHandler(handler http.Handler) Handler {
+	return HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps Params) {
+		if len(ps) > 0 {
+			for _, p := range ps {
+				req.SetPathValue(p.Key, p.Value)
+			}
+
+			req = req.WithContext(context.WithValue(req.Context(), ParamsKey, ps))
+		}
+
+		handler.ServeHTTP(rw, req)
+	})
+}
@@ -0,0 +1,155 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Use(main *testing.T) {
+	main.Run("RouterAndHandlerMiddleware", func(t *testing.T) {
+		r := stdrouter.New()
+		r.Use(writeMiddleware("a"), writeMiddleware("b"))
+
+		require.NoError(t, r.RegisterHandler("GET", "/foo", writeHandler("h"), writeMiddleware("c")))
+		require.NoError(t, r.RegisterHandler("GET", "/bar", writeHandler("h")))
+
+		require.Equal(t, "abch", serve(r, "GET", "/foo").Body.String())
+		require.Equal(t, "abh", serve(r, "GET", "/bar").Body.String())
+	})
+
+	main.Run("AppliedOnce", func(t *testing.T) {
+		r := stdrouter.New()
+
+		calls := 0
+		r.Use(func(next stdrouter.Handler) stdrouter.Handler {
+			calls++
+			return next
+		})
+
+		// global, not found and method not allowed handlers
+		require.Equal(t, 3, calls)
+
+		require.NoError(t, r.RegisterHandler("GET", "/foo", writeHandler("h")))
+		require.Equal(t, 4, calls)
+
+		serve(r, "GET", "/foo")
+		serve(r, "GET", "/not/found")
+		serve(r, "UNSUPPORTED", "/foo")
+		require.Equal(t, 4, calls)
+	})
+
+	main.Run("HandlersAddedBeforeUseNotWrapped", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.NoError(t, r.RegisterHandler("GET", "/foo", writeHandler("h")))
+		r.Use(writeMiddleware("a"))
+
+		require.Equal(t, "h", serve(r, "GET", "/foo").Body.String())
+	})
+
+	main.Run("AddStdHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		r.Use(writeMiddleware("a"))
+
+		hID := r.AddStdHandler(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte("h"))
+		}), writeMiddleware("b"))
+		require.NoError(t, r.Add("GET", "/foo", hID))
+
+		require.Equal(t, "abh", serve(r, "GET", "/foo").Body.String())
+	})
+
+	main.Run("GroupMiddleware", func(t *testing.T) {
+		r := stdrouter.New()
+		r.Use(writeMiddleware("a"))
+
+		g := r.Group("/api", writeMiddleware("b"))
+		require.NoError(t, g.RegisterHandler("GET", "/foo", writeHandler("h"), writeMiddleware("c")))
+
+		require.Equal(t, "abch", serve(r, "GET", "/api/foo").Body.String())
+	})
+
+	main.Run("Fallbacks", func(t *testing.T) {
+		r := stdrouter.New()
+		r.GlobalHandler = writeHandler("global")
+		r.Use(writeMiddleware("a"), writeMiddleware("b"))
+
+		require.NoError(t, r.Add("GET", "/foo", 123))
+
+		require.Equal(t, "abglobal", serve(r, "GET", "/foo").Body.String())
+
+		r = stdrouter.New()
+		r.Use(headerMiddleware("X-Mw", "a"))
+
+		rw := serve(r, "GET", "/not/found")
+		require.Equal(t, http.StatusNotFound, rw.Result().StatusCode)
+		require.Equal(t, "a", rw.Header().Get("X-Mw"))
+
+		rw = serve(r, "UNSUPPORTED", "/foo")
+		require.Equal(t, http.StatusMethodNotAllowed, rw.Result().StatusCode)
+		require.Equal(t, "a", rw.Header().Get("X-Mw"))
+
+		r = stdrouter.New()
+		r.GlobalHandler = writeHandler("global")
+		r.Use(writeMiddleware("a"), writeMiddleware("b"))
+		require.NoError(t, r.Add("GET", "/foo", 123))
+
+		h, err := r.FindHandler("GET", "/foo")
+		require.NoError(t, err)
+		rw = serve(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			h.ServeHTTP(rw, req, nil)
+		}), "GET", "/foo")
+		require.Equal(t, "abglobal", rw.Body.String())
+	})
+
+	main.Run("FallbacksSetAfterUse", func(t *testing.T) {
+		r := stdrouter.New()
+		r.Use(writeMiddleware("a"))
+
+		r.GlobalHandler = writeHandler("global")
+		r.PageNotFoundHandler = func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte("not found"))
+		}
+		r.MethodNotAllowedHandler = func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte("not allowed"))
+		}
+		require.NoError(t, r.Add("GET", "/foo", 123))
+
+		require.Equal(t, "aglobal", serve(r, "GET", "/foo").Body.String())
+
+		require.Equal(t, "anot found", serve(r, "GET", "/not/found").Body.String())
+
+		require.Equal(t, "anot allowed", serve(r, "UNSUPPORTED", "/foo").Body.String())
+
+		// replacing a handler again takes effect too
+		r.GlobalHandler = writeHandler("other")
+		require.Equal(t, "aother", serve(r, "GET", "/foo").Body.String())
+
+		r.GlobalHandler = nil
+		_, err := r.FindHandler("GET", "/foo")
+		require.EqualError(t, err, "handler not found")
+	})
+
+	main.Run("FallbacksWithoutMiddleware", func(t *testing.T) {
+		r := stdrouter.New()
+		r.GlobalHandler = writeHandler("global")
+
+		require.NoError(t, r.Add("GET", "/foo", 123))
+
+		require.Equal(t, "global", serve(r, "GET", "/foo").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/not/found").Result().StatusCode)
+		require.Equal(t, http.StatusMethodNotAllowed, serve(r, "UNSUPPORTED", "/foo").Result().StatusCode)
+	})
+}
+
+func headerMiddleware(key, value string) stdrouter.Middleware {
+	return func(next stdrouter.Handler) stdrouter.Handler {
+		return stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+			rw.Header().Set(key, value)
+			next.ServeHTTP(rw, req, ps)
+		})
+	}
+}
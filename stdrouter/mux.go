@@ -0,0 +1,157 @@
+package stdrouter
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Handle registers handler for a net/http.ServeMux style pattern: [METHOD ][HOST]/[PATH].
+//
+// The pattern without a method matches any method. {name} matches a path segment, {name...} matches the rest of
+// the path, a trailing slash matches the whole subtree unless the pattern ends with {$}.
+// Host specific patterns take precedence over patterns without a host.
+// Like in ServeMux, a GET pattern also matches HEAD requests unless a HEAD route matches them.
+func (r *Router) Handle(pattern string, handler http.Handler) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+
+	mp, err := parseMuxPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	hID := r.AddStdHandler(handler)
+	for i, path := range mp.paths {
+		if err := r.add(mp.host, mp.method, path, hID); err != nil {
+			for _, added := range mp.paths[:i] {
+				_ = r.remove(mp.host, mp.method, added)
+			}
+			// the added routes are rolled back, so RemoveHandler cannot refuse
+			_ = r.RemoveHandler(hID)
+
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+
+	r.handlePatterns[hID] = mp
+	r.handleIDs[pattern] = hID
+
+	return nil
+}
+
+// HandleFunc registers handler function for a net/http.ServeMux style pattern, see Handle.
+func (r *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) error {
+	if handler == nil {
+		return fmt.Errorf("handler is nil")
+	}
+
+	return r.Handle(pattern, http.HandlerFunc(handler))
+}
+
+type muxPattern struct {
+	pattern string
+	method  string
+	host    string
+	paths   []string
+}
+
+// parseMuxPattern translates a ServeMux pattern to radix paths.
+func parseMuxPattern(pattern string) (muxPattern, error) {
+	mp := muxPattern{
+		pattern: pattern,
+		method:  MethodAny,
+	}
+
+	rest := pattern
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		mp.method = rest[:i]
+		rest = strings.TrimLeft(rest[i+1:], " \t")
+
+		if methodIndexOf(mp.method) == -1 {
+			return muxPattern{}, fmt.Errorf("pattern %q: method %q not allowed", pattern, mp.method)
+		}
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i == -1 {
+		return muxPattern{}, fmt.Errorf("pattern %q: path must start with /", pattern)
+	}
+	mp.host = rest[:i]
+	rest = rest[i:]
+
+	var path strings.Builder
+	var names []string
+	exact := false
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			path.WriteString(rest)
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			return muxPattern{}, fmt.Errorf("pattern %q: no right bracket", pattern)
+		}
+		end += start
+
+		path.WriteString(rest[:start])
+		name := rest[start+1 : end]
+		rest = rest[end+1:]
+
+		if name != "$" {
+			param := strings.TrimSuffix(name, "...")
+			if slices.Contains(names, param) {
+				return muxPattern{}, fmt.Errorf("pattern %q: duplicate wildcard name %q", pattern, param)
+			}
+			names = append(names, param)
+		}
+
+		switch {
+		case name == "$":
+			if rest != "" {
+				return muxPattern{}, fmt.Errorf("pattern %q: {$} must be at the end", pattern)
+			}
+			if !strings.HasSuffix(path.String(), "/") {
+				return muxPattern{}, fmt.Errorf("pattern %q: {$} must follow a slash", pattern)
+			}
+
+			exact = true
+		case strings.HasSuffix(name, "..."):
+			if rest != "" {
+				return muxPattern{}, fmt.Errorf("pattern %q: {%s} must be at the end", pattern, name)
+			}
+
+			if p := path.String(); strings.HasSuffix(p, "/") {
+				// the wildcard may match an empty rest
+				mp.paths = append(mp.paths, p)
+			}
+
+			path.WriteString("{*" + strings.TrimSuffix(name, "...") + "}")
+			exact = true
+		default:
+			path.WriteString("{" + name + "}")
+		}
+	}
+
+	p := path.String()
+	mp.paths = append(mp.paths, p)
+	if !exact && strings.HasSuffix(p, "/") {
+		// anonymous wildcard matches the subtree
+		mp.paths = append(mp.paths, p+"{*}")
+	}
+
+	return mp, nil
+}
+
+// hostOf strips the port from the request host.
+func hostOf(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i != -1 && strings.LastIndexByte(host, ']') < i {
+		return host[:i]
+	}
+
+	return host
+}
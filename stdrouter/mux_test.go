@@ -0,0 +1,161 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_HandlePattern(main *testing.T) {
+	pathValueHandler := func(name string) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = rw.Write([]byte(name + "=" + req.PathValue(name)))
+		}
+	}
+
+	main.Run("Method", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("GET /items/{id}", pathValueHandler("id")))
+		require.NoError(t, r.Handle("POST  /items", pathValueHandler("id")))
+
+		require.Equal(t, "id=123", serve(r, "GET", "/items/123").Body.String())
+		require.Equal(t, "id=", serve(r, "POST", "/items").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "DELETE", "/items/123").Result().StatusCode)
+	})
+
+	main.Run("AnyMethod", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.HandleFunc("/items/{id}", pathValueHandler("id")))
+
+		require.Equal(t, "id=123", serve(r, "GET", "/items/123").Body.String())
+		require.Equal(t, "id=123", serve(r, "DELETE", "/items/123").Body.String())
+	})
+
+	main.Run("Wildcard", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("/files/{path...}", pathValueHandler("path")))
+
+		require.Equal(t, "path=a/b.txt", serve(r, "GET", "/files/a/b.txt").Body.String())
+		require.Equal(t, "path=", serve(r, "GET", "/files/").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/files").Result().StatusCode)
+	})
+
+	main.Run("Subtree", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("/static/", pathValueHandler("id")))
+		require.NoError(t, r.Handle("/static/favicon.ico", writeStdHandler("favicon")))
+
+		require.Equal(t, "id=", serve(r, "GET", "/static/").Body.String())
+		require.Equal(t, "id=", serve(r, "GET", "/static/js/app.js").Body.String())
+		require.Equal(t, "favicon", serve(r, "GET", "/static/favicon.ico").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/static").Result().StatusCode)
+	})
+
+	main.Run("Root", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("/", writeStdHandler("root")))
+		require.NoError(t, r.Handle("/foo", writeStdHandler("foo")))
+
+		require.Equal(t, "root", serve(r, "GET", "/").Body.String())
+		require.Equal(t, "root", serve(r, "GET", "/bar/baz").Body.String())
+		require.Equal(t, "foo", serve(r, "GET", "/foo").Body.String())
+	})
+
+	main.Run("Exact", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("/exact/{$}", writeStdHandler("exact")))
+
+		require.Equal(t, "exact", serve(r, "GET", "/exact/").Body.String())
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/exact/foo").Result().StatusCode)
+	})
+
+	main.Run("HeadMatchesGet", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+		require.NoError(t, r.Handle("GET /items/{id}", pathValueHandler("id")))
+		require.NoError(t, r.Handle("GET /pages/{id}", writeStdHandler("get")))
+		require.NoError(t, r.Handle("HEAD /pages/{id}", writeStdHandler("head")))
+		require.NoError(t, r.Handle("/any/{id}", writeStdHandler("any")))
+		require.NoError(t, r.Handle("GET /any/{id}", writeStdHandler("get")))
+		require.NoError(t, r.Add("GET", "/added/{id}", r.AddStdHandler(writeStdHandler("added"))))
+
+		require.Equal(t, "id=123", serve(r, "HEAD", "/items/123").Body.String())
+		require.Equal(t, "head", serve(r, "HEAD", "/pages/123").Body.String())
+		require.Equal(t, "get", serve(r, "HEAD", "/any/123").Body.String())
+		// only patterns registered with Handle serve HEAD requests
+		require.Equal(t, http.StatusNotFound, serve(r, "HEAD", "/added/123").Result().StatusCode)
+
+		var route stdrouter.RouteInfo
+		require.NoError(t, r.HandleFunc("GET /matched/{id}", func(_ http.ResponseWriter, req *http.Request) {
+			route, _ = r.MatchedRoute(req)
+		}))
+		serve(r, "HEAD", "/matched/123")
+		require.Equal(t, "GET", route.Method)
+		require.Equal(t, "GET /matched/{id}", route.Pattern)
+	})
+
+	main.Run("Host", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("/foo", writeStdHandler("default")))
+		require.NoError(t, r.Handle("GET example.com/foo", writeStdHandler("example")))
+		require.NoError(t, r.Handle("example.com/bar", writeStdHandler("example-bar")))
+
+		serveHost := func(host, path string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", path, http.NoBody)
+			req.Host = host
+			rw := httptest.NewRecorder()
+			r.ServeHTTP(rw, req)
+
+			return rw
+		}
+
+		require.Equal(t, "example", serveHost("example.com", "/foo").Body.String())
+		require.Equal(t, "example", serveHost("example.com:8080", "/foo").Body.String())
+		require.Equal(t, "example-bar", serveHost("example.com", "/bar").Body.String())
+		require.Equal(t, "default", serveHost("other.com", "/foo").Body.String())
+		require.Equal(t, http.StatusNotFound, serveHost("other.com", "/bar").Result().StatusCode)
+
+		routes := r.Routes()
+		require.Len(t, routes, 3)
+		require.Equal(t, "", routes[0].Host)
+		require.Equal(t, "example.com", routes[1].Host)
+		require.Equal(t, "/foo", routes[1].Pattern)
+		require.Equal(t, "GET", routes[1].Method)
+	})
+
+	main.Run("Invalid", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.EqualError(t, r.Handle("BREW /coffee", writeStdHandler("")), `pattern "BREW /coffee": method "BREW" not allowed`)
+		require.EqualError(t, r.Handle("GET coffee", writeStdHandler("")), `pattern "GET coffee": path must start with /`)
+		require.EqualError(t, r.Handle("/coffee/{id", writeStdHandler("")), `pattern "/coffee/{id": no right bracket`)
+		require.EqualError(t, r.Handle("/coffee/{$}/foo", writeStdHandler("")), `pattern "/coffee/{$}/foo": {$} must be at the end`)
+		require.EqualError(t, r.Handle("/coffee{$}", writeStdHandler("")), `pattern "/coffee{$}": {$} must follow a slash`)
+		require.EqualError(t, r.Handle("/coffee/{id...}/foo", writeStdHandler("")), `pattern "/coffee/{id...}/foo": {id...} must be at the end`)
+		require.EqualError(t, r.Handle("/coffee/{id}/{id}", writeStdHandler("")), `pattern "/coffee/{id}/{id}": duplicate wildcard name "id"`)
+		require.EqualError(t, r.Handle("/coffee/{id}/{id...}", writeStdHandler("")), `pattern "/coffee/{id}/{id...}": duplicate wildcard name "id"`)
+		require.EqualError(t, r.Handle("/coffee", nil), `handler is nil`)
+	})
+
+	main.Run("Conflict", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.NoError(t, r.Handle("/coffee", writeStdHandler("")))
+		require.EqualError(t, r.Handle("/coffee", writeStdHandler("")), `pattern "/coffee": ANY: "/coffee" conflicts with "/coffee" (key 1) at column 1: path already taken`)
+
+		// the subtree wildcard conflicts, the exact path is rolled back
+		require.NoError(t, r.Add(stdrouter.MethodAny, "/tea/{*}", 123))
+		require.EqualError(t, r.Handle("/tea/", writeStdHandler("")), `pattern "/tea/": ANY: "/tea/{*}" conflicts with "/tea/{*}" (key 123) at column 6: path already taken`)
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/tea/").Result().StatusCode)
+		require.Len(t, r.Routes(), 2)
+	})
+}
+
+func writeStdHandler(body string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(body))
+	})
+}
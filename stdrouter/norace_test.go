@@ -0,0 +1,5 @@
+//go:build !race
+
+package stdrouter_test
+
+const raceEnabled = false
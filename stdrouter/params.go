@@ -0,0 +1,50 @@
+package stdrouter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/makasim/httprouter/internal/param"
+)
+
+// ParamError is returned by typed Params accessors when a param is missing or cannot be converted.
+// Router.HandleParamError turns it into a 400 response.
+type ParamError = param.Error
+
+var ErrParamMissing = param.ErrMissing
+
+// Int returns the param value parsed as a decimal int.
+func (ps Params) Int(name string) (int, error) {
+	return param.Int(name, ps.Get(name))
+}
+
+// Uint64 returns the param value parsed as a decimal uint64.
+func (ps Params) Uint64(name string) (uint64, error) {
+	return param.Uint64(name, ps.Get(name))
+}
+
+// Bool returns the param value parsed with strconv.ParseBool.
+func (ps Params) Bool(name string) (bool, error) {
+	return param.Bool(name, ps.Get(name))
+}
+
+// UUID returns the param value parsed as a UUID in the canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form.
+func (ps Params) UUID(name string) ([16]byte, error) {
+	return param.UUID(name, ps.Get(name))
+}
+
+// Time returns the param value parsed with time.Parse and the given layout.
+func (ps Params) Time(name, layout string) (time.Time, error) {
+	return param.Time(name, ps.Get(name), layout)
+}
+
+// HandleParamError responds to a request which params failed to convert.
+// It calls ParamErrorHandler if set, otherwise it replies with 400 Bad Request and the error text.
+func (r *Router) HandleParamError(rw http.ResponseWriter, req *http.Request, err error) {
+	if r.ParamErrorHandler != nil {
+		r.ParamErrorHandler(rw, req, err)
+		return
+	}
+
+	http.Error(rw, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,94 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams_Typed(t *testing.T) {
+	// conversions are tested in internal/param, the accessors only look values up
+	ps := stdrouter.Params{
+		{Key: "id", Value: "123"},
+		{Key: "big", Value: "18446744073709551615"},
+		{Key: "flag", Value: "true"},
+		{Key: "uuid", Value: "123E4567-e89b-12d3-a456-426614174000"},
+		{Key: "date", Value: "2024-02-29"},
+	}
+
+	i, err := ps.Int("id")
+	require.NoError(t, err)
+	require.Equal(t, 123, i)
+
+	u, err := ps.Uint64("big")
+	require.NoError(t, err)
+	require.Equal(t, uint64(18446744073709551615), u)
+
+	b, err := ps.Bool("flag")
+	require.NoError(t, err)
+	require.True(t, b)
+
+	id, err := ps.UUID("uuid")
+	require.NoError(t, err)
+	require.Equal(t, byte(0x12), id[0])
+
+	tm, err := ps.Time("date", time.DateOnly)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), tm)
+
+	_, err = ps.Int("unknown")
+	require.EqualError(t, err, `param "unknown": param missing`)
+	require.ErrorIs(t, err, stdrouter.ErrParamMissing)
+
+	var paramErr *stdrouter.ParamError
+	require.ErrorAs(t, err, &paramErr)
+	require.Equal(t, "unknown", paramErr.Name)
+	require.Equal(t, "int", paramErr.Type)
+}
+
+func TestRouter_HandleParamError(main *testing.T) {
+	handler := func(r *stdrouter.Router) stdrouter.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+			id, err := ps.Int("id")
+			if err != nil {
+				r.HandleParamError(rw, req, err)
+				return
+			}
+
+			_, _ = rw.Write([]byte(strconv.Itoa(id * 2)))
+		}
+	}
+
+	main.Run("Default", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", handler(r)))
+
+		require.Equal(t, "246", serve(r, "GET", "/users/123").Body.String())
+
+		rec := serve(r, "GET", "/users/abc")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, "param \"id\": invalid int \"abc\": invalid syntax\n", rec.Body.String())
+	})
+
+	main.Run("Custom", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ParamErrorHandler = func(rw http.ResponseWriter, _ *http.Request, err error) {
+			var paramErr *stdrouter.ParamError
+			require.ErrorAs(t, err, &paramErr)
+
+			rw.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = rw.Write([]byte(paramErr.Name))
+		}
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", handler(r)))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/users/abc", http.NoBody))
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		require.Equal(t, "id", rec.Body.String())
+	})
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package stdrouter_test
+
+// raceEnabled skips allocation tests, sync.Pool drops items randomly under the race detector.
+const raceEnabled = true
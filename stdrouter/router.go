@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"runtime/debug"
+	"runtime/pprof"
 	"slices"
 	"sync"
 
 	"github.com/makasim/httprouter/radix"
 )
 
+// Handler serves a matched route.
+//
+// Params passed to ServeHTTP are owned by the router and reused for later requests once ServeHTTP returns,
+// unless Router.HeapParams is enabled. Use Params.Clone to keep them, for example in a started goroutine.
+// Values reference the request path and stay valid.
 type Handler interface {
 	ServeHTTP(http.ResponseWriter, *http.Request, Params)
 }
@@ -22,6 +29,15 @@ func (f HandlerFunc) ServeHTTP(rw http.ResponseWriter, r *http.Request, p Params
 
 type HandlerID int
 
+// PanicInfo describes a panic recovered by the router.
+type PanicInfo struct {
+	Value interface{}
+	Stack []byte
+	// Pattern and HandlerID of the matched route, empty if the panic happened before a route matched or none did.
+	Pattern   string
+	HandlerID HandlerID
+}
+
 type Param struct {
 	Key   string
 	Value string
@@ -32,12 +48,26 @@ type Params []Param
 func (ps Params) Get(name string) string {
 	for _, p := range ps {
 		if p.Key == name {
+			if p.Value == poisonedValue {
+				panic(ErrParamsReleased)
+			}
 			return p.Value
 		}
 	}
 	return ""
 }
 
+// Clone returns a copy of ps that is owned by the caller and may be kept after the handler returns.
+func (ps Params) Clone() Params {
+	for _, p := range ps {
+		if p.Value == poisonedValue {
+			panic(ErrParamsReleased)
+		}
+	}
+
+	return slices.Clone(ps)
+}
+
 func (ps *Params) Set(name, value string) {
 	if value == "" {
 		for i, p := range *ps {
@@ -62,12 +92,38 @@ func (ps *Params) Set(name, value string) {
 	})
 }
 
+// addCaptures appends params captured by radix.Tree.SearchCaptures. Values reference the searched path,
+// wildcard names are stored without the leading asterisk.
+func (ps *Params) addCaptures(c *radix.Captures) {
+	for i := 0; i < c.Len(); i++ {
+		name := c.At(i).Name
+		if name != "" && name[0] == '*' {
+			name = name[1:]
+		}
+		if name == "" {
+			continue // anonymous wildcard
+		}
+
+		*ps = append(*ps, Param{
+			Key:   name,
+			Value: c.Value(i),
+		})
+	}
+}
+
+// ErrParamsReleased is the panic value of Params.Get and Params.Clone called on params poisoned by Router.PoisonParams.
+var ErrParamsReleased = fmt.Errorf("params used after the handler returned")
+
+// poisonedValue replaces param values released with Router.PoisonParams enabled.
+const poisonedValue = "\x00stdrouter: params released"
+
 type paramsKey struct{}
 
 var ParamsKey = paramsKey{}
 
 // ParamsFromContext pulls the URL parameters from a request context,
-// or returns nil if none are present.
+// or returns empty params if none are present.
+// The router puts params into the context of handlers added with AddStdHandler.
 func ParamsFromContext(ctx context.Context) Params {
 	p, ok := ctx.Value(ParamsKey).(Params)
 	if !ok {
@@ -81,20 +137,79 @@ var HandlerKeyUserValue = "stdprouter.handler_id"
 
 const MethodAny = "ANY"
 const methodAnyIndex = 9
+const methodGetIndex = 0
+const methodHeadIndex = 1
 
 type Router struct {
 	PageNotFoundHandler     http.HandlerFunc
 	MethodNotAllowedHandler http.HandlerFunc
 	GlobalHandler           Handler
 
+	// PanicHandler, if set, recovers panics from handlers and responds to the client.
+	// http.ErrAbortHandler is not recovered.
+	PanicHandler func(http.ResponseWriter, *http.Request, PanicInfo)
+
+	// ColonSyntax makes Add and Remove accept julienschmidt/httprouter paths:
+	// :name and *name segments are translated to {name} and the {**name} catch-all,
+	// so like in julienschmidt/httprouter /static/*filepath matches /static/ and its value keeps the leading slash.
+	ColonSyntax bool
+
+	// SaveMatchedRoute sets http.Request.Pattern to the matched route pattern before invoking the handler,
+	// MatchedRoute resolves the rest of the route from it. Routes registered with Handle set the pattern passed to Handle.
+	SaveMatchedRoute bool
+
+	// RemoveHandlerMode defines what RemoveHandler does with routes registered for the handler.
+	RemoveHandlerMode RemoveMode
+
+	// ParamErrorHandler, if set, is called by HandleParamError instead of replying with 400 Bad Request.
+	ParamErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+	// ErrorHandler, if set, handles errors returned by handlers registered with RegisterErrorHandler.
+	ErrorHandler func(http.ResponseWriter, *http.Request, RouteInfo, error)
+
+	// JSONErrorEncoder, if set, writes errors of handlers registered with JSON instead of EncodeJSONError.
+	JSONErrorEncoder func(http.ResponseWriter, *http.Request, error)
+
+	// ProfileLabels makes ServeHTTP run the matched handler under pprof.Do with route and method labels,
+	// so CPU and goroutine profiles can be split by route pattern. The handler gets the labeled request context,
+	// use it as the parent for nested pprof.Do calls to keep the labels.
+	ProfileLabels bool
+
+	// HeapParams makes ServeHTTP allocate Params for every request instead of taking them from a pool,
+	// so handlers own the params and may keep them without Params.Clone.
+	HeapParams bool
+
+	// PoisonParams is a debug mode that detects use of params after the handler returned.
+	// Returned params are not reused, their values are overwritten instead,
+	// and Params.Get and Params.Clone on them panic with ErrParamsReleased.
+	PoisonParams bool
+
 	handlers       []Handler
+	meta           map[HandlerID]Meta
+	handlerRoutes  map[HandlerID]map[routeRef]struct{}
+	hosts          map[string][]radix.Tree
 	freeHandlerIds []HandlerID
+	// handlePatterns are patterns registered with Handle by handler id, handleIDs maps them back to the id.
+	// GET patterns serve HEAD requests too, and a matched route reports the ServeMux pattern.
+	handlePatterns map[HandlerID]muxPattern
+	handleIDs      map[string]HandlerID
+
+	middleware              []Middleware
+	globalHandler           Handler
+	pageNotFoundHandler     Handler
+	methodNotAllowedHandler Handler
 
 	Trees []radix.Tree
 
 	paramsPool sync.Pool
 }
 
+var capturesPool = sync.Pool{
+	New: func() interface{} {
+		return radix.NewCaptures(8)
+	},
+}
+
 func New() *Router {
 	return &Router{
 		PageNotFoundHandler: func(rw http.ResponseWriter, _ *http.Request) {
@@ -107,8 +222,13 @@ func New() *Router {
 		Trees: make([]radix.Tree, 10),
 
 		handlers:       make([]Handler, 1), // 0 is nil handler
+		meta:           make(map[HandlerID]Meta),
+		handlerRoutes:  make(map[HandlerID]map[routeRef]struct{}),
 		freeHandlerIds: make([]HandlerID, 0),
 
+		handlePatterns: make(map[HandlerID]muxPattern),
+		handleIDs:      make(map[string]HandlerID),
+
 		paramsPool: sync.Pool{
 			New: func() interface{} {
 				return new(Params)
@@ -118,75 +238,239 @@ func New() *Router {
 }
 
 func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var route *radix.Route
+	if r.PanicHandler != nil {
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.handlePanic(rw, req, route, rec)
+			}
+		}()
+	}
+
 	i := methodIndexOf(req.Method)
 	if i == -1 {
-		r.MethodNotAllowedHandler(rw, req)
+		r.serveMethodNotAllowed(rw, req)
 		return
 	}
 
 	ps := r.getParams()
 	defer r.putParams(ps)
 
-	hID := r.Trees[i].Search(req.URL.Path, func(n string, v interface{}) {
-		v1, ok := v.(string)
-		if !ok {
-			return // skip
-		}
-
-		*ps = append(*ps, Param{
-			Key:   n,
-			Value: v1,
-		})
-	})
-	if hID == 0 {
-		if ps != nil {
-			*ps = (*ps)[:0]
-		}
+	route = r.searchRoute(req, i, ps)
+	if route == nil {
+		r.servePageNotFound(rw, req)
+		return
+	}
 
-		hID = r.Trees[methodAnyIndex].Search(req.URL.Path, func(n string, v interface{}) {
-			v1, ok := v.(string)
-			if !ok {
-				return // skip
-			}
+	hID := route.Key
+	if r.SaveMatchedRoute {
+		req.Pattern = r.routePattern(route)
+	}
 
-			*ps = append(*ps, Param{
-				Key:   n,
-				Value: v1,
-			})
+	if r.ProfileLabels {
+		labels := pprof.Labels("route", route.Pattern, "method", req.Method)
+		pprof.Do(req.Context(), labels, func(ctx context.Context) {
+			r.dispatch(rw, req.WithContext(ctx), hID, *ps)
 		})
-
-		if hID == 0 {
-			r.PageNotFoundHandler(rw, req)
-			return
-		}
+		return
 	}
 
+	r.dispatch(rw, req, hID, *ps)
+}
+
+func (r *Router) dispatch(rw http.ResponseWriter, req *http.Request, hID uint64, ps Params) {
 	maxHID := len(r.handlers) - 1
 	if int(hID) <= maxHID {
 		if h := r.handlers[int(hID)]; h != nil {
-			h.ServeHTTP(rw, req, *ps)
+			h.ServeHTTP(rw, req, ps)
 			return
 		}
 	}
 
 	if r.GlobalHandler != nil {
-
-		r.GlobalHandler.ServeHTTP(rw, req, *ps)
+		r.serveGlobal(rw, req, ps)
 		return
 	}
 
-	r.PageNotFoundHandler(rw, req)
+	r.servePageNotFound(rw, req)
+}
+
+func (r *Router) searchRoute(req *http.Request, methodIndex int, ps *Params) *radix.Route {
+	c := capturesPool.Get().(*radix.Captures)
+	defer capturesPool.Put(c)
+
+	route := r.searchHostTrees(req.Host, methodIndex, req.URL.Path, c)
+
+	*ps = (*ps)[:0]
+	if route != nil {
+		ps.addCaptures(c)
+	}
+
+	return route
+}
+
+// MatchedRoute returns the route matched for req, when SaveMatchedRoute is enabled.
+// The route is found by http.Request.Pattern in the same trees ServeHTTP searched, it does not allocate.
+// For routes registered with Handle the pattern is the one passed to Handle, like in ServeMux.
+func (r *Router) MatchedRoute(req *http.Request) (RouteInfo, bool) {
+	methodIndex := methodIndexOf(req.Method)
+	if req.Pattern == "" || methodIndex == -1 {
+		return RouteInfo{}, false
+	}
+
+	hID, ok := r.handleIDs[req.Pattern]
+	if !ok {
+		return r.findPatternRoute(req, methodIndex)
+	}
+
+	if info, ok := r.findPatternRoute(req, methodIndex); ok && info.HandlerID != hID && !r.matchesHandler(req, methodIndex, hID) {
+		// a route added with Add has the same pattern
+		return info, true
+	}
+
+	mp := r.handlePatterns[hID]
+	return RouteInfo{
+		Host:      mp.host,
+		Method:    mp.method,
+		Pattern:   mp.pattern,
+		HandlerID: hID,
+		Meta:      r.meta[hID],
+	}, true
+}
+
+func (r *Router) findPatternRoute(req *http.Request, methodIndex int) (RouteInfo, bool) {
+	if len(r.hosts) > 0 {
+		host := hostOf(req.Host)
+		if trees, ok := r.hosts[host]; ok {
+			if info, ok := r.findRoute(trees, methodIndex, req.Pattern); ok {
+				info.Host = host
+				return info, true
+			}
+		}
+	}
+
+	return r.findRoute(r.Trees, methodIndex, req.Pattern)
+}
+
+func (r *Router) findRoute(trees []radix.Tree, methodIndex int, pattern string) (RouteInfo, bool) {
+	method := methods[methodIndex]
+	route := trees[methodIndex].Find(pattern)
+	if route == nil && methodIndex == methodHeadIndex {
+		if route = r.headGetRoute(trees[methodGetIndex].Find(pattern)); route != nil {
+			method = http.MethodGet
+		}
+	}
+	if route == nil && methodIndex != methodAnyIndex {
+		method = MethodAny
+		route = trees[methodAnyIndex].Find(pattern)
+	}
+	if route == nil {
+		return RouteInfo{}, false
+	}
+
+	hID := HandlerID(route.Key)
+	return RouteInfo{
+		Method:    method,
+		Pattern:   r.routePattern(route),
+		HandlerID: hID,
+		Meta:      r.meta[hID],
+	}, true
+}
+
+// matchesHandler reports whether ServeHTTP serves req with the handler.
+func (r *Router) matchesHandler(req *http.Request, methodIndex int, hID HandlerID) bool {
+	c := capturesPool.Get().(*radix.Captures)
+	defer capturesPool.Put(c)
+
+	route := r.searchHostTrees(req.Host, methodIndex, req.URL.Path, c)
+	return route != nil && HandlerID(route.Key) == hID
+}
+
+// routePattern returns the pattern passed to Handle for its routes and the route pattern otherwise.
+func (r *Router) routePattern(route *radix.Route) string {
+	if mp, ok := r.handlePatterns[HandlerID(route.Key)]; ok && slices.Contains(mp.paths, route.Pattern) {
+		return mp.pattern
+	}
+
+	return route.Pattern
+}
+
+// searchHostTrees searches the host routes first and falls back to the routes without a host.
+func (r *Router) searchHostTrees(host string, methodIndex int, path string, c *radix.Captures) *radix.Route {
+	if len(r.hosts) > 0 {
+		if trees, ok := r.hosts[hostOf(host)]; ok {
+			if route := r.searchTrees(trees, methodIndex, path, c); route != nil {
+				return route
+			}
+		}
+	}
+
+	return r.searchTrees(r.Trees, methodIndex, path, c)
+}
+
+// searchTrees searches the method tree and falls back to the MethodAny tree.
+// A HEAD request first falls back to the GET patterns registered with Handle, as in ServeMux.
+func (r *Router) searchTrees(trees []radix.Tree, methodIndex int, path string, c *radix.Captures) *radix.Route {
+	route := trees[methodIndex].SearchCaptures(path, c)
+	if route != nil || methodIndex == methodAnyIndex {
+		return route
+	}
+
+	if methodIndex == methodHeadIndex && len(r.handlePatterns) > 0 {
+		if route := r.headGetRoute(trees[methodGetIndex].SearchCaptures(path, c)); route != nil {
+			return route
+		}
+	}
+
+	return trees[methodAnyIndex].SearchCaptures(path, c)
+}
+
+// headGetRoute returns the GET route if it was registered with Handle and may serve HEAD requests.
+func (r *Router) headGetRoute(route *radix.Route) *radix.Route {
+	if route == nil {
+		return nil
+	}
+	if r.handlePatterns[HandlerID(route.Key)].method != http.MethodGet {
+		return nil
+	}
+
+	return route
 }
 
-func (r *Router) AddHandler(handler Handler) HandlerID {
+func (r *Router) handlePanic(rw http.ResponseWriter, req *http.Request, route *radix.Route, rec interface{}) {
+	if rec == http.ErrAbortHandler {
+		panic(rec)
+	}
+
+	info := PanicInfo{
+		Value: rec,
+		Stack: debug.Stack(),
+	}
+	if route != nil {
+		info.Pattern = route.Pattern
+		info.HandlerID = HandlerID(route.Key)
+	}
+
+	r.PanicHandler(rw, req, info)
+}
+
+// AddHandler adds handler wrapped with the router-wide middleware followed by the given one.
+// The returned id is used to register routes with Add.
+func (r *Router) AddHandler(handler Handler, middleware ...Middleware) HandlerID {
 	if handler == nil {
 		panic("handler is nil")
 	}
 
-	if len(r.freeHandlerIds) > 0 {
-		id := r.freeHandlerIds[len(r.freeHandlerIds)-1]
-		r.freeHandlerIds = r.freeHandlerIds[:len(r.freeHandlerIds)-1]
+	handler = r.wrap(handler, middleware...)
+
+	for i := len(r.freeHandlerIds) - 1; i >= 0; i-- {
+		id := r.freeHandlerIds[i]
+		if len(r.handlerRoutes[id]) > 0 {
+			// dangling routes would be served by the new handler
+			continue
+		}
 
+		r.freeHandlerIds = slices.Delete(r.freeHandlerIds, i, i+1)
 		r.handlers[id] = handler
 
 		return id
@@ -198,21 +482,29 @@ func (r *Router) AddHandler(handler Handler) HandlerID {
 	return HandlerID(id)
 }
 
+// FindHandler returns the handler ServeHTTP would call for a request without a host route.
+// Use FindHostHandler to take host routes registered with Handle into account.
 func (r *Router) FindHandler(method, path string) (Handler, error) {
+	return r.FindHostHandler("", method, path)
+}
+
+// FindHostHandler works like FindHandler but first searches the routes registered for host, as ServeHTTP does for
+// http.Request.Host. A port in host is ignored.
+func (r *Router) FindHostHandler(host, method, path string) (Handler, error) {
 	i := methodIndexOf(method)
 	if i == -1 {
 		return nil, fmt.Errorf("unsupported method %v", method)
 	}
 
-	hID := r.Trees[i].Search(path, func(n string, v interface{}) {})
-	if hID == 0 && i != methodAnyIndex {
-		hID = r.Trees[methodAnyIndex].Search(path, func(n string, v interface{}) {})
-
-		if hID == 0 {
-			return nil, fmt.Errorf("path %v not found", path)
-		}
+	c := capturesPool.Get().(*radix.Captures)
+	route := r.searchHostTrees(host, i, path, c)
+	capturesPool.Put(c)
+	if route == nil {
+		return nil, fmt.Errorf("path %v not found", path)
 	}
 
+	hID := HandlerID(route.Key)
+
 	maxHID := len(r.handlers) - 1
 	if int(hID) <= maxHID {
 		if h := r.handlers[int(hID)]; h != nil {
@@ -221,15 +513,42 @@ func (r *Router) FindHandler(method, path string) (Handler, error) {
 	}
 
 	if r.GlobalHandler != nil {
+		if r.globalHandler != nil {
+			return r.globalHandler, nil
+		}
 		return r.GlobalHandler, nil
 	}
 
 	return nil, fmt.Errorf("handler not found")
 }
 
-func (r *Router) RemoveHandler(hID HandlerID) {
+// RemoveHandler frees the handler id. Depending on RemoveHandlerMode the handler routes are kept,
+// removed, or RemoveHandler fails with ErrHandlerHasRoutes.
+// A freed id is not reused while routes still point at it, see DanglingRoutes.
+// Removing an unknown or already removed id fails without changing the router.
+func (r *Router) RemoveHandler(hID HandlerID) error {
+	if hID < 1 || int(hID) >= len(r.handlers) || r.handlers[hID] == nil {
+		return fmt.Errorf("handler %d not found", hID)
+	}
+
+	switch r.RemoveHandlerMode {
+	case RefuseRoutes:
+		if n := len(r.handlerRoutes[hID]); n > 0 {
+			return fmt.Errorf("handler %d: %w: %d", hID, ErrHandlerHasRoutes, n)
+		}
+	case CascadeRoutes:
+		r.RemoveKey(hID)
+	}
+
 	r.handlers[hID] = nil
+	delete(r.meta, hID)
+	if mp, ok := r.handlePatterns[hID]; ok {
+		delete(r.handleIDs, mp.pattern)
+		delete(r.handlePatterns, hID)
+	}
 	r.freeHandlerIds = append(r.freeHandlerIds, hID)
+
+	return nil
 }
 
 func (r *Router) GetHandler(hID HandlerID) (Handler, error) {
@@ -243,18 +562,25 @@ func (r *Router) GetHandler(hID HandlerID) (Handler, error) {
 	return r.handlers[hID], nil
 }
 
-func (r *Router) AddStdHandler(handler http.Handler) HandlerID {
-	return r.AddHandler(HandlerFunc(func(rw http.ResponseWriter, req *http.Request, _ Params) {
-		handler.ServeHTTP(rw, req)
-	}))
+func (r *Router) AddStdHandler(handler http.Handler, middleware ...Middleware) HandlerID {
+	return r.AddHandler(stdHandler(handler), middleware...)
 }
 
-func (r *Router) RegisterHandler(method, path string, handler Handler) error {
-	hID := r.AddHandler(handler)
+func (r *Router) RegisterHandler(method, path string, handler Handler, middleware ...Middleware) error {
+	hID := r.AddHandler(handler, middleware...)
 	return r.Add(method, path, hID)
 }
 
 func (r *Router) Add(method, path string, handlerID HandlerID) error {
+	path, err := r.convertPath(path)
+	if err != nil {
+		return err
+	}
+
+	return r.add("", method, path, handlerID)
+}
+
+func (r *Router) add(host, method, path string, handlerID HandlerID) error {
 	methodIndex := methodIndexOf(method)
 	if methodIndex == -1 {
 		return fmt.Errorf("method not allowed")
@@ -263,20 +589,85 @@ func (r *Router) Add(method, path string, handlerID HandlerID) error {
 		return fmt.Errorf("path empty")
 	}
 
-	var err error
-	tree := r.Trees[methodIndex]
+	trees := r.hostTrees(host)
+	tree, err := trees[methodIndex].Insert(path, uint64(handlerID))
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	trees[methodIndex] = tree
+	r.indexRoute(handlerID, routeRef{host: host, method: methods[methodIndex], pattern: path})
+
+	return nil
+}
 
-	tree, err = tree.Insert(path, uint64(handlerID))
+// hostTrees returns the trees of host specific routes creating them if needed, or the default trees for an empty host.
+func (r *Router) hostTrees(host string) []radix.Tree {
+	if host == "" {
+		return r.Trees
+	}
+
+	if r.hosts == nil {
+		r.hosts = make(map[string][]radix.Tree)
+	}
+	if _, ok := r.hosts[host]; !ok {
+		r.hosts[host] = make([]radix.Tree, len(methods))
+	}
+
+	return r.hosts[host]
+}
+
+// Replace adds a route for method and path or swaps the handler id of an existing one in a single step,
+// so there is no moment when the route is missing like with Remove followed by Add.
+// It returns the previous handler id, or 0 if the route did not exist.
+func (r *Router) Replace(method, path string, handlerID HandlerID) (HandlerID, error) {
+	path, err := r.convertPath(path)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	methodIndex := methodIndexOf(method)
+	if methodIndex == -1 {
+		return 0, fmt.Errorf("method not allowed")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("path empty")
+	}
+
+	tree, prev, err := r.Trees[methodIndex].Upsert(path, uint64(handlerID))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", method, err)
 	}
 
 	r.Trees[methodIndex] = tree
 
-	return nil
+	ref := routeRef{method: methods[methodIndex], pattern: path}
+	if prev != 0 {
+		r.unindexRoute(HandlerID(prev), ref)
+	}
+	r.indexRoute(handlerID, ref)
+
+	return HandlerID(prev), nil
 }
 
 func (r *Router) Remove(method, path string) error {
+	path, err := r.convertPath(path)
+	if err != nil {
+		return err
+	}
+
+	return r.remove("", method, path)
+}
+
+func (r *Router) convertPath(path string) (string, error) {
+	if !r.ColonSyntax {
+		return path, nil
+	}
+
+	return radix.ConvertColonSyntax(path)
+}
+
+func (r *Router) remove(host, method, path string) error {
 	methodIndex := methodIndexOf(method)
 	if methodIndex == -1 {
 		return fmt.Errorf("method not allowed")
@@ -285,28 +676,104 @@ func (r *Router) Remove(method, path string) error {
 		return fmt.Errorf("path empty")
 	}
 
-	var err error
-	tree := r.Trees[methodIndex]
-
-	tree, err = tree.Delete(path)
+	trees := r.hostTrees(host)
+	tree, key, err := trees[methodIndex].Delete(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s: %w", method, err)
 	}
 
-	r.Trees[methodIndex] = tree
+	trees[methodIndex] = tree
+	r.unindexRoute(HandlerID(key), routeRef{host: host, method: methods[methodIndex], pattern: path})
+
 	return nil
 }
 
+// RemoveKey removes every route registered for the handler id, host specific routes included.
+// It returns the number of removed routes. The handler itself is kept, see RemoveHandlerAndRoutes.
+func (r *Router) RemoveKey(hID HandlerID) int {
+	return r.removeRoutes(func(tree radix.Tree) (radix.Tree, []radix.Route) {
+		return tree.DeleteKey(uint64(hID))
+	})
+}
+
+// RemovePrefix removes every route which pattern starts with prefix, host specific routes included.
+// It returns the number of removed routes.
+func (r *Router) RemovePrefix(prefix string) int {
+	return r.removeRoutes(func(tree radix.Tree) (radix.Tree, []radix.Route) {
+		return tree.DeletePrefix(prefix)
+	})
+}
+
+// RemoveHandlerAndRoutes removes the handler together with its routes.
+// It returns the number of removed routes.
+func (r *Router) RemoveHandlerAndRoutes(hID HandlerID) int {
+	n := r.RemoveKey(hID)
+	// there are no routes left, so RemoveHandler cannot refuse
+	_ = r.RemoveHandler(hID)
+
+	return n
+}
+
+func (r *Router) removeRoutes(del func(tree radix.Tree) (radix.Tree, []radix.Route)) int {
+	n := r.removeHostRoutes("", r.Trees, del)
+	for host, trees := range r.hosts {
+		n += r.removeHostRoutes(host, trees, del)
+	}
+
+	return n
+}
+
+func (r *Router) removeHostRoutes(host string, trees []radix.Tree, del func(tree radix.Tree) (radix.Tree, []radix.Route)) int {
+	n := 0
+	for i := range trees {
+		var removed []radix.Route
+		trees[i], removed = del(trees[i])
+		for _, route := range removed {
+			r.unindexRoute(HandlerID(route.Key), routeRef{host: host, method: methods[i], pattern: route.Pattern})
+		}
+
+		n += len(removed)
+	}
+
+	return n
+}
+
 func (r *Router) getParams() *Params {
+	if r.HeapParams {
+		return new(Params)
+	}
+
 	ps, _ := r.paramsPool.Get().(*Params)
 	*ps = (*ps)[0:0] // reset slice
 	return ps
 }
 
 func (r *Router) putParams(ps *Params) {
-	if ps != nil {
-		r.paramsPool.Put(ps)
+	if ps == nil || r.HeapParams {
+		return
+	}
+
+	if r.PoisonParams {
+		for i := range *ps {
+			(*ps)[i].Value = poisonedValue
+		}
+		return
 	}
+
+	r.paramsPool.Put(ps)
+}
+
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+	MethodAny,
 }
 
 func methodIndexOf(method string) int {
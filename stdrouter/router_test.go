@@ -1,13 +1,16 @@
 package stdrouter_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"runtime/pprof"
 	"strconv"
 	"testing"
 
+	"github.com/makasim/httprouter/radix"
 	"github.com/makasim/httprouter/stdrouter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -79,6 +82,27 @@ func TestRouter_Insert(main *testing.T) {
 		require.EqualError(t, err, "path empty")
 	})
 
+	main.Run("Conflict", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.NoError(t, r.Add("GET", "/users/{id}", 10))
+
+		err := r.Add("GET", "/users/{id}", 11)
+		require.EqualError(t, err, `GET: "/users/{id}" conflicts with "/users/{id}" (key 10) at column 8: path already taken`)
+		require.ErrorIs(t, err, radix.ErrPathAlreadyTaken)
+
+		var conflictErr *radix.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.Equal(t, "/users/{id}", conflictErr.Pattern)
+		require.Equal(t, uint64(10), conflictErr.Key)
+
+		// a conflict deep in the tree leaves the live tree intact
+		require.NoError(t, r.Add("GET", "/users/{id}/posts/{post}", 12))
+		before := r.Routes()
+		require.Error(t, r.Add("GET", "/users/{id}/posts/{name}/comments", 13))
+		require.Equal(t, before, r.Routes())
+	})
+
 	main.Run("OK", func(t *testing.T) {
 		r := stdrouter.New()
 
@@ -811,6 +835,88 @@ func TestRouter_Delete(t *testing.T) {
 	require.Equal(t, http.StatusNotFound, rw.Result().StatusCode)
 }
 
+func TestRouter_Replace(t *testing.T) {
+	r := stdrouter.New()
+	h1ID := r.AddHandler(writeHandler("one"))
+	h2ID := r.AddHandler(writeHandler("two"))
+
+	prev, err := r.Replace("GET", "/foo/{bar}", h1ID)
+	require.NoError(t, err)
+	require.Equal(t, stdrouter.HandlerID(0), prev)
+	require.Equal(t, "one", serve(r, "GET", "/foo/baz").Body.String())
+
+	prev, err = r.Replace("GET", "/foo/{bar}", h2ID)
+	require.NoError(t, err)
+	require.Equal(t, h1ID, prev)
+	require.Equal(t, "two", serve(r, "GET", "/foo/baz").Body.String())
+	require.Len(t, r.Routes(), 1)
+
+	_, err = r.Replace("GET", "/foo/{baz}", h1ID)
+	require.ErrorIs(t, err, radix.ErrParamNameConflict)
+	require.Equal(t, "two", serve(r, "GET", "/foo/baz").Body.String())
+
+	_, err = r.Replace("unsupported", "/foo", h1ID)
+	require.EqualError(t, err, "method not allowed")
+}
+
+func TestRouter_RemoveNotFound(t *testing.T) {
+	r := stdrouter.New()
+
+	require.NoError(t, r.Add("GET", "/foo", 1))
+
+	err := r.Remove("POST", "/foo")
+	require.EqualError(t, err, `POST: delete "/foo": path not found`)
+	require.ErrorIs(t, err, radix.ErrPathNotFound)
+}
+
+func TestRouter_RemoveKey(t *testing.T) {
+	r := stdrouter.New()
+	h1ID := r.AddHandler(writeHandler("one"))
+	h2ID := r.AddHandler(writeHandler("two"))
+
+	require.NoError(t, r.Add("GET", "/foo", h1ID))
+	require.NoError(t, r.Add("POST", "/foo/{bar}", h1ID))
+	require.NoError(t, r.Add("GET", "/bar", h2ID))
+	require.NoError(t, r.Handle("example.org/foo", writeStdHandler("host")))
+
+	require.Equal(t, 2, r.RemoveKey(h1ID))
+	require.Equal(t, http.StatusNotFound, serve(r, "GET", "/foo").Code)
+	require.Equal(t, "two", serve(r, "GET", "/bar").Body.String())
+
+	// the host route has its own handler id
+	require.Len(t, r.Routes(), 2)
+}
+
+func TestRouter_RemovePrefix(t *testing.T) {
+	r := stdrouter.New()
+	hID := r.AddHandler(writeHandler("one"))
+
+	require.NoError(t, r.Add("GET", "/legacy/foo", hID))
+	require.NoError(t, r.Add("POST", "/legacy/{*path}", hID))
+	require.NoError(t, r.Add("GET", "/foo", hID))
+	require.NoError(t, r.HandleFunc("GET example.com/legacy/bar", func(http.ResponseWriter, *http.Request) {}))
+
+	require.Equal(t, 3, r.RemovePrefix("/legacy/"))
+	require.Equal(t, http.StatusNotFound, serve(r, "GET", "/legacy/foo").Code)
+	require.Equal(t, http.StatusNotFound, serve(r, "POST", "/legacy/bar").Code)
+	require.Equal(t, "one", serve(r, "GET", "/foo").Body.String())
+	require.Len(t, r.Routes(), 1)
+}
+
+func TestRouter_RemoveHandlerAndRoutes(t *testing.T) {
+	r := stdrouter.New()
+	hID := r.AddHandler(writeHandler("one"))
+
+	require.NoError(t, r.Add("GET", "/foo", hID))
+	require.NoError(t, r.Add("GET", "/foo/{bar}", hID))
+
+	require.Equal(t, 2, r.RemoveHandlerAndRoutes(hID))
+	require.Empty(t, r.Routes())
+
+	_, err := r.GetHandler(hID)
+	require.EqualError(t, err, "handler not found")
+}
+
 func TestRouter_FindHandler_HandleComplexParametrizedRouting(main *testing.T) {
 	main.Run("WhenParametrizedRouteAfterSimpleRoutes_OK", func(t *testing.T) {
 		r := stdrouter.New()
@@ -1012,6 +1118,50 @@ func TestRouter_FindHandler(main *testing.T) {
 		h.ServeHTTP(responseRecorder, request, nil)
 		assert.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
 	})
+	main.Run("FindHandler_HeadFallsBackToHandleGet", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("GET /foo", writeStdHandler("foo")))
+		require.NoError(t, r.RegisterHandler("GET", "/bar", writeHandler("bar")))
+
+		h, err := r.FindHandler("HEAD", "/foo")
+		require.NoError(t, err)
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest("HEAD", "/foo", http.NoBody), nil)
+		assert.Equal(t, "foo", rw.Body.String())
+
+		_, err = r.FindHandler("HEAD", "/bar")
+		assert.EqualError(t, err, "path /bar not found")
+	})
+	main.Run("FindHostHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.Handle("example.com/foo", writeStdHandler("host")))
+		require.NoError(t, r.Handle("/foo", writeStdHandler("nohost")))
+		require.NoError(t, r.Handle("example.com/bar/{id}", writeStdHandler("hostbar")))
+
+		find := func(host, method, path string) string {
+			h, err := r.FindHostHandler(host, method, path)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, httptest.NewRequest(method, path, http.NoBody), nil)
+			return rw.Body.String()
+		}
+
+		assert.Equal(t, "host", find("example.com", "GET", "/foo"))
+		assert.Equal(t, "host", find("example.com:8080", "GET", "/foo"))
+		assert.Equal(t, "hostbar", find("example.com", "GET", "/bar/1"))
+		assert.Equal(t, "nohost", find("other.com", "GET", "/foo"))
+		assert.Equal(t, "nohost", find("", "GET", "/foo"))
+
+		h, err := r.FindHandler("GET", "/foo")
+		require.NoError(t, err)
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest("GET", "/foo", http.NoBody), nil)
+		assert.Equal(t, "nohost", rw.Body.String())
+
+		_, err = r.FindHostHandler("other.com", "GET", "/bar/1")
+		assert.EqualError(t, err, "path /bar/1 not found")
+	})
 	main.Run("FindHandler_OK", func(t *testing.T) {
 		r := stdrouter.New()
 		mockHandler := func(resp string) stdrouter.Handler {
@@ -1311,3 +1461,482 @@ func TestParams(t *testing.T) {
 	require.Equal(t, "value2", ps.Get("key2"))
 	require.Equal(t, "", ps.Get("key3"))
 }
+
+func TestRouter_SaveMatchedRoute(main *testing.T) {
+	main.Run("Enabled", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+
+		var actParams stdrouter.Params
+		var actPattern string
+		var actRoute stdrouter.RouteInfo
+		h := stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+			actParams = append(stdrouter.Params{}, ps...)
+			actPattern = req.Pattern
+			actRoute, _ = r.MatchedRoute(req)
+		})
+
+		hID := r.AddHandlerWithMeta(h, stdrouter.Meta{Name: "users"})
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+		require.NoError(t, r.Add(stdrouter.MethodAny, "/any", hID))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, "/users/{id}", actPattern)
+		require.Equal(t, stdrouter.Params{{Key: "id", Value: "123"}}, actParams)
+		require.Equal(t, stdrouter.RouteInfo{
+			Method:    "GET",
+			Pattern:   "/users/{id}",
+			HandlerID: hID,
+			Meta:      stdrouter.Meta{Name: "users"},
+		}, actRoute)
+
+		serve(r, "POST", "/any")
+		require.Equal(t, "/any", actPattern)
+		require.Empty(t, actParams)
+		require.Equal(t, stdrouter.RouteInfo{
+			Method:    stdrouter.MethodAny,
+			Pattern:   "/any",
+			HandlerID: hID,
+			Meta:      stdrouter.Meta{Name: "users"},
+		}, actRoute)
+	})
+
+	main.Run("Host", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+
+		var actRoute stdrouter.RouteInfo
+		require.NoError(t, r.Handle("GET example.com/users/{id}", http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			actRoute, _ = r.MatchedRoute(req)
+			require.Equal(t, stdrouter.Params{{Key: "id", Value: "123"}}, stdrouter.ParamsFromContext(req.Context()))
+		})))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, stdrouter.RouteInfo{
+			Host:      "example.com",
+			Method:    "GET",
+			Pattern:   "GET example.com/users/{id}",
+			HandlerID: 1,
+		}, actRoute)
+	})
+
+	main.Run("HandlePattern", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+
+		var actPattern string
+		var actRoute stdrouter.RouteInfo
+		handler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			actPattern = req.Pattern
+			actRoute, _ = r.MatchedRoute(req)
+		})
+		require.NoError(t, r.Handle("GET /items/{id}", handler))
+		require.NoError(t, r.Handle("/files/{p...}", handler))
+		require.NoError(t, r.Handle("/api/", handler))
+		require.NoError(t, r.Handle("/same", handler))
+		addedID := r.AddHandler(stdrouter.HandlerFunc(func(_ http.ResponseWriter, req *http.Request, _ stdrouter.Params) {
+			actPattern = req.Pattern
+			actRoute, _ = r.MatchedRoute(req)
+		}))
+		require.NoError(t, r.Add("GET", "/same", addedID))
+
+		for _, tt := range []struct {
+			method     string
+			path       string
+			expPattern string
+			expMethod  string
+			expID      stdrouter.HandlerID
+		}{
+			{method: "GET", path: "/items/1", expPattern: "GET /items/{id}", expMethod: "GET", expID: 1},
+			{method: "HEAD", path: "/items/1", expPattern: "GET /items/{id}", expMethod: "GET", expID: 1},
+			{method: "GET", path: "/files/", expPattern: "/files/{p...}", expMethod: stdrouter.MethodAny, expID: 2},
+			{method: "GET", path: "/files/a/b", expPattern: "/files/{p...}", expMethod: stdrouter.MethodAny, expID: 2},
+			{method: "GET", path: "/api/", expPattern: "/api/", expMethod: stdrouter.MethodAny, expID: 3},
+			{method: "GET", path: "/api/users", expPattern: "/api/", expMethod: stdrouter.MethodAny, expID: 3},
+			{method: "POST", path: "/same", expPattern: "/same", expMethod: stdrouter.MethodAny, expID: 4},
+			{method: "GET", path: "/same", expPattern: "/same", expMethod: "GET", expID: addedID},
+		} {
+			actPattern, actRoute = "", stdrouter.RouteInfo{}
+			serve(r, tt.method, tt.path)
+
+			require.Equal(t, tt.expPattern, actPattern, tt.method+" "+tt.path)
+			require.Equal(t, stdrouter.RouteInfo{
+				Method:    tt.expMethod,
+				Pattern:   tt.expPattern,
+				HandlerID: tt.expID,
+			}, actRoute, tt.method+" "+tt.path)
+		}
+
+		require.NoError(t, r.RemoveHandler(1))
+		req := httptest.NewRequest("GET", "/items/1", http.NoBody)
+		req.Pattern = "GET /items/{id}"
+		_, ok := r.MatchedRoute(req)
+		require.False(t, ok)
+	})
+
+	main.Run("GlobalHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+
+		var actRoute stdrouter.RouteInfo
+		r.GlobalHandler = stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+			actRoute, _ = r.MatchedRoute(req)
+		})
+		require.NoError(t, r.Add("GET", "/foo", 123))
+
+		serve(r, "GET", "/foo")
+		require.Equal(t, "/foo", actRoute.Pattern)
+		require.Equal(t, stdrouter.HandlerID(123), actRoute.HandlerID)
+	})
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var actPattern string
+		var ok bool
+		require.NoError(t, r.RegisterHandler("GET", "/foo", stdrouter.HandlerFunc(
+			func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+				actPattern = req.Pattern
+				_, ok = r.MatchedRoute(req)
+			})))
+
+		serve(r, "GET", "/foo")
+		require.Equal(t, "", actPattern)
+		require.False(t, ok)
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		if raceEnabled {
+			t.Skip("sync.Pool drops items randomly under the race detector")
+		}
+
+		r := stdrouter.New()
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", stdrouter.HandlerFunc(
+			func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {})))
+
+		req := httptest.NewRequest("GET", "/users/123", http.NoBody)
+		rw := httptest.NewRecorder()
+
+		disabled := testing.AllocsPerRun(100, func() {
+			r.ServeHTTP(rw, req)
+		})
+
+		r.SaveMatchedRoute = true
+		enabled := testing.AllocsPerRun(100, func() {
+			r.ServeHTTP(rw, req)
+		})
+
+		require.Equal(t, disabled, enabled)
+	})
+}
+
+func TestRouter_ProfileLabels(main *testing.T) {
+	goroutines := func() string {
+		var buf bytes.Buffer
+		_ = pprof.Lookup("goroutine").WriteTo(&buf, 1)
+		return buf.String()
+	}
+
+	main.Run("Enabled", func(t *testing.T) {
+		r := stdrouter.New()
+		r.ProfileLabels = true
+
+		var profile, route, method string
+		require.NoError(t, r.RegisterHandler("POST", "/users/{id}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, req *http.Request, _ stdrouter.Params) {
+				profile = goroutines()
+				route, _ = pprof.Label(req.Context(), "route")
+				method, _ = pprof.Label(req.Context(), "method")
+			})))
+
+		serve(r, "POST", "/users/123")
+		require.Contains(t, profile, `"route":"/users/{id}"`)
+		require.Contains(t, profile, `"method":"POST"`)
+		require.Equal(t, "/users/{id}", route)
+		require.Equal(t, "POST", method)
+
+		// labels are removed once the handler returns
+		require.NotContains(t, goroutines(), `"route":"/users/{id}"`)
+	})
+
+	main.Run("Disabled", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var profile string
+		var ok bool
+		require.NoError(t, r.RegisterHandler("POST", "/users/{id}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, req *http.Request, _ stdrouter.Params) {
+				profile = goroutines()
+				_, ok = pprof.Label(req.Context(), "route")
+			})))
+
+		serve(r, "POST", "/users/123")
+		require.NotEmpty(t, profile)
+		require.NotContains(t, profile, `"route":"/users/{id}"`)
+		require.False(t, ok)
+	})
+}
+
+func TestRouter_PanicHandler(main *testing.T) {
+	panicHandler := func(info *stdrouter.PanicInfo) func(http.ResponseWriter, *http.Request, stdrouter.PanicInfo) {
+		return func(rw http.ResponseWriter, _ *http.Request, actInfo stdrouter.PanicInfo) {
+			*info = actInfo
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+
+	main.Run("Handler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var info stdrouter.PanicInfo
+		r.PanicHandler = panicHandler(&info)
+
+		hID := r.AddHandler(stdrouter.HandlerFunc(func(http.ResponseWriter, *http.Request, stdrouter.Params) {
+			panic("oops")
+		}))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+
+		rw := serve(r, "GET", "/users/123")
+		require.Equal(t, http.StatusInternalServerError, rw.Result().StatusCode)
+		require.Equal(t, "oops", info.Value)
+		require.Contains(t, string(info.Stack), "panic")
+		require.Equal(t, "/users/{id}", info.Pattern)
+		require.Equal(t, hID, info.HandlerID)
+	})
+
+	main.Run("GlobalHandler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var info stdrouter.PanicInfo
+		r.PanicHandler = panicHandler(&info)
+		r.GlobalHandler = stdrouter.HandlerFunc(func(http.ResponseWriter, *http.Request, stdrouter.Params) {
+			panic("oops")
+		})
+		require.NoError(t, r.Add(stdrouter.MethodAny, "/foo", 123))
+
+		rw := serve(r, "POST", "/foo")
+		require.Equal(t, http.StatusInternalServerError, rw.Result().StatusCode)
+		require.Equal(t, "oops", info.Value)
+		require.Equal(t, "/foo", info.Pattern)
+		require.Equal(t, stdrouter.HandlerID(123), info.HandlerID)
+	})
+
+	main.Run("NotFoundHandlers", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var info stdrouter.PanicInfo
+		r.PanicHandler = panicHandler(&info)
+		r.PageNotFoundHandler = func(http.ResponseWriter, *http.Request) {
+			panic("not found")
+		}
+		r.MethodNotAllowedHandler = func(http.ResponseWriter, *http.Request) {
+			panic("method not allowed")
+		}
+
+		rw := serve(r, "GET", "/foo")
+		require.Equal(t, http.StatusInternalServerError, rw.Result().StatusCode)
+		require.Equal(t, "not found", info.Value)
+		require.Equal(t, "", info.Pattern)
+		require.Equal(t, stdrouter.HandlerID(0), info.HandlerID)
+
+		rw = serve(r, "UNSUPPORTED", "/foo")
+		require.Equal(t, http.StatusInternalServerError, rw.Result().StatusCode)
+		require.Equal(t, "method not allowed", info.Value)
+	})
+
+	main.Run("ErrAbortHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		r.PanicHandler = func(http.ResponseWriter, *http.Request, stdrouter.PanicInfo) {
+			t.Fatal("must not be called")
+		}
+		require.NoError(t, r.RegisterHandler("GET", "/foo", stdrouter.HandlerFunc(
+			func(http.ResponseWriter, *http.Request, stdrouter.Params) {
+				panic(http.ErrAbortHandler)
+			})))
+
+		require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			serve(r, "GET", "/foo")
+		})
+	})
+
+	main.Run("NoPanicHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		require.NoError(t, r.RegisterHandler("GET", "/foo", stdrouter.HandlerFunc(
+			func(http.ResponseWriter, *http.Request, stdrouter.Params) {
+				panic("oops")
+			})))
+
+		require.PanicsWithValue(t, "oops", func() {
+			serve(r, "GET", "/foo")
+		})
+	})
+
+	main.Run("ParamsReleased", func(t *testing.T) {
+		r := stdrouter.New()
+		r.SaveMatchedRoute = true
+		r.PanicHandler = func(http.ResponseWriter, *http.Request, stdrouter.PanicInfo) {}
+
+		require.NoError(t, r.RegisterHandler("GET", "/panic/{id}", stdrouter.HandlerFunc(
+			func(http.ResponseWriter, *http.Request, stdrouter.Params) {
+				panic("oops")
+			})))
+
+		var actParams stdrouter.Params
+		require.NoError(t, r.RegisterHandler("GET", "/foo/{name}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				actParams = append(stdrouter.Params{}, ps...)
+			})))
+
+		for i := 0; i < 10; i++ {
+			serve(r, "GET", "/panic/1")
+			serve(r, "GET", "/foo/bar")
+
+			require.Equal(t, stdrouter.Params{{Key: "name", Value: "bar"}}, actParams)
+		}
+	})
+}
+
+func TestRouter_Params(main *testing.T) {
+	main.Run("Clone", func(t *testing.T) {
+		r := stdrouter.New()
+		r.PoisonParams = true
+
+		var kept stdrouter.Params
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				kept = ps.Clone()
+			})))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, stdrouter.Params{{Key: "id", Value: "123"}}, kept)
+		require.Equal(t, "123", kept.Get("id"))
+		require.Nil(t, stdrouter.Params(nil).Clone())
+	})
+
+	main.Run("PoisonParams", func(t *testing.T) {
+		r := stdrouter.New()
+		r.PoisonParams = true
+
+		var kept stdrouter.Params
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				if kept == nil {
+					require.Equal(t, "123", ps.Get("id"))
+					kept = ps
+				}
+			})))
+
+		serve(r, "GET", "/users/123")
+		require.PanicsWithValue(t, stdrouter.ErrParamsReleased, func() { kept.Get("id") })
+		require.PanicsWithValue(t, stdrouter.ErrParamsReleased, func() { kept.Clone() })
+		require.Equal(t, "", kept.Get("unknown"))
+
+		// poisoned params are not reused
+		serve(r, "GET", "/users/456")
+		require.PanicsWithValue(t, stdrouter.ErrParamsReleased, func() { kept.Get("id") })
+	})
+
+	main.Run("HeapParams", func(t *testing.T) {
+		r := stdrouter.New()
+		r.HeapParams = true
+		r.PoisonParams = true
+
+		var kept []stdrouter.Params
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				kept = append(kept, ps)
+			})))
+
+		serve(r, "GET", "/users/123")
+		serve(r, "GET", "/users/456")
+		require.Equal(t, "123", kept[0].Get("id"))
+		require.Equal(t, "456", kept[1].Get("id"))
+	})
+
+	main.Run("NoAllocs", func(t *testing.T) {
+		if raceEnabled {
+			t.Skip("sync.Pool drops items randomly under the race detector")
+		}
+
+		r := stdrouter.New()
+
+		var id, order, path string
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}/orders/{order}/files/{*path}", stdrouter.HandlerFunc(
+			func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+				id, order, path = ps.Get("id"), ps.Get("order"), ps.Get("path")
+			})))
+
+		req := httptest.NewRequest("GET", "/users/123/orders/456/files/a/b.txt", http.NoBody)
+		rw := httptest.NewRecorder()
+
+		require.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+			r.ServeHTTP(rw, req)
+		}))
+		require.Equal(t, "123", id)
+		require.Equal(t, "456", order)
+		require.Equal(t, "a/b.txt", path)
+	})
+
+	main.Run("Handler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var actParams stdrouter.Params
+		h := stdrouter.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request, ps stdrouter.Params) {
+			actParams = append(stdrouter.Params{}, ps...)
+		})
+		require.NoError(t, r.RegisterHandler("GET", "/users/{id}/orders/{order}", h))
+		require.NoError(t, r.RegisterHandler(stdrouter.MethodAny, "/static/{*path}", h))
+
+		serve(r, "GET", "/users/123/orders/456")
+		require.Len(t, actParams, 2)
+		require.Equal(t, "123", actParams.Get("id"))
+		require.Equal(t, "456", actParams.Get("order"))
+
+		serve(r, "POST", "/static/js/app.js")
+		require.Equal(t, stdrouter.Params{{Key: "path", Value: "js/app.js"}}, actParams)
+	})
+
+	main.Run("StdHandler", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var pathValues []string
+		var ctxParams stdrouter.Params
+		hID := r.AddStdHandler(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			pathValues = []string{req.PathValue("id"), req.PathValue("path")}
+			ctxParams = stdrouter.ParamsFromContext(req.Context())
+		}))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+		require.NoError(t, r.Add("GET", "/static/{*path}", hID))
+		require.NoError(t, r.Add("GET", "/status", hID))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, []string{"123", ""}, pathValues)
+		require.Equal(t, stdrouter.Params{{Key: "id", Value: "123"}}, ctxParams)
+
+		serve(r, "GET", "/static/js/app.js")
+		require.Equal(t, []string{"", "js/app.js"}, pathValues)
+		require.Equal(t, "js/app.js", ctxParams.Get("path"))
+
+		serve(r, "GET", "/status")
+		require.Equal(t, []string{"", ""}, pathValues)
+		require.Equal(t, stdrouter.Params{}, ctxParams)
+	})
+
+	main.Run("StdHandlerMiddleware", func(t *testing.T) {
+		r := stdrouter.New()
+
+		var pathValue string
+		hID := r.AddStdHandler(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			pathValue = req.PathValue("id")
+		}), func(next stdrouter.Handler) stdrouter.Handler {
+			return stdrouter.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, ps stdrouter.Params) {
+				ps.Set("id", "overridden")
+				next.ServeHTTP(rw, req, ps)
+			})
+		})
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+
+		serve(r, "GET", "/users/123")
+		require.Equal(t, "overridden", pathValue)
+	})
+}
@@ -0,0 +1,99 @@
+package stdrouter
+
+import (
+	"fmt"
+	"sort"
+)
+
+var ErrHandlerHasRoutes = fmt.Errorf("handler has routes")
+
+// RemoveMode defines what RemoveHandler does with routes registered for the removed handler.
+type RemoveMode uint8
+
+const (
+	// KeepRoutes leaves the routes in place, they fall through to GlobalHandler or PageNotFoundHandler.
+	KeepRoutes RemoveMode = iota
+	// RefuseRoutes makes RemoveHandler fail with ErrHandlerHasRoutes while the handler has routes.
+	RefuseRoutes
+	// CascadeRoutes makes RemoveHandler remove the handler routes.
+	CascadeRoutes
+)
+
+// routeRef identifies a route in the reverse index from handler ids to routes.
+type routeRef struct {
+	host    string
+	method  string
+	pattern string
+}
+
+func (r *Router) indexRoute(hID HandlerID, ref routeRef) {
+	refs, ok := r.handlerRoutes[hID]
+	if !ok {
+		refs = make(map[routeRef]struct{})
+		r.handlerRoutes[hID] = refs
+	}
+
+	refs[ref] = struct{}{}
+}
+
+func (r *Router) unindexRoute(hID HandlerID, ref routeRef) {
+	refs := r.handlerRoutes[hID]
+	delete(refs, ref)
+
+	if len(refs) == 0 {
+		delete(r.handlerRoutes, hID)
+	}
+}
+
+// HandlerRoutes returns routes registered for the handler id ordered by host, method and pattern.
+// Routes inserted into Trees directly are not tracked.
+func (r *Router) HandlerRoutes(hID HandlerID) []RouteInfo {
+	refs := r.handlerRoutes[hID]
+	if len(refs) == 0 {
+		return nil
+	}
+
+	routes := make([]RouteInfo, 0, len(refs))
+	for ref := range refs {
+		routes = append(routes, RouteInfo{
+			Host:      ref.host,
+			Method:    ref.method,
+			Pattern:   ref.pattern,
+			HandlerID: hID,
+			Meta:      r.meta[hID],
+		})
+	}
+
+	sortRoutes(routes)
+	return routes
+}
+
+// DanglingRoutes returns routes which handler was removed with RemoveHandler while the routes were kept.
+// Such routes are served by GlobalHandler or PageNotFoundHandler, and their handler ids are not reused until
+// the routes are removed.
+func (r *Router) DanglingRoutes() []RouteInfo {
+	var routes []RouteInfo
+	for _, hID := range r.freeHandlerIds {
+		routes = append(routes, r.HandlerRoutes(hID)...)
+	}
+
+	sortRoutes(routes)
+	return routes
+}
+
+func sortRoutes(routes []RouteInfo) {
+	sort.Slice(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Method != b.Method {
+			return methodIndexOf(a.Method) < methodIndexOf(b.Method)
+		}
+		if a.Pattern != b.Pattern {
+			return a.Pattern < b.Pattern
+		}
+
+		return a.HandlerID < b.HandlerID
+	})
+}
@@ -0,0 +1,138 @@
+package stdrouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/makasim/httprouter/stdrouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_HandlerRoutes(main *testing.T) {
+	main.Run("Index", func(t *testing.T) {
+		r := stdrouter.New()
+		hID := r.AddHandler(writeHandler("user"))
+		otherID := r.AddHandler(writeHandler("other"))
+
+		require.NoError(t, r.Add("POST", "/users/{id}", hID))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+		require.NoError(t, r.Add("GET", "/other", otherID))
+
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/users/{id}", HandlerID: hID},
+			{Method: "POST", Pattern: "/users/{id}", HandlerID: hID},
+		}, r.HandlerRoutes(hID))
+
+		require.NoError(t, r.Remove("POST", "/users/{id}"))
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/users/{id}", HandlerID: hID},
+		}, r.HandlerRoutes(hID))
+
+		prev, err := r.Replace("GET", "/users/{id}", otherID)
+		require.NoError(t, err)
+		require.Equal(t, hID, prev)
+		require.Empty(t, r.HandlerRoutes(hID))
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/other", HandlerID: otherID},
+			{Method: "GET", Pattern: "/users/{id}", HandlerID: otherID},
+		}, r.HandlerRoutes(otherID))
+
+		require.Equal(t, 1, r.RemovePrefix("/users/"))
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/other", HandlerID: otherID},
+		}, r.HandlerRoutes(otherID))
+	})
+
+	main.Run("Host", func(t *testing.T) {
+		r := stdrouter.New()
+
+		require.NoError(t, r.Handle("GET example.org/static/", writeStdHandler("static")))
+
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Host: "example.org", Method: "GET", Pattern: "/static/", HandlerID: 1},
+			{Host: "example.org", Method: "GET", Pattern: "/static/{*}", HandlerID: 1},
+		}, r.HandlerRoutes(1))
+	})
+
+	main.Run("Meta", func(t *testing.T) {
+		r := stdrouter.New()
+
+		meta := stdrouter.Meta{Name: "get-user"}
+		require.NoError(t, r.RegisterHandlerWithMeta("GET", "/users/{id}", writeHandler("user"), meta))
+
+		routes := r.HandlerRoutes(1)
+		require.Len(t, routes, 1)
+		require.Equal(t, meta, routes[0].Meta)
+	})
+}
+
+func TestRouter_RemoveHandlerMode(main *testing.T) {
+	main.Run("KeepRoutes", func(t *testing.T) {
+		r := stdrouter.New()
+		hID := r.AddHandler(writeHandler("user"))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+
+		require.NoError(t, r.RemoveHandler(hID))
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/users/1").Code)
+		require.Equal(t, []stdrouter.RouteInfo{
+			{Method: "GET", Pattern: "/users/{id}", HandlerID: hID},
+		}, r.DanglingRoutes())
+
+		// the id is not reused while routes point at it
+		newID := r.AddHandler(writeHandler("new"))
+		require.NotEqual(t, hID, newID)
+		require.Equal(t, http.StatusNotFound, serve(r, "GET", "/users/1").Code)
+
+		require.NoError(t, r.Remove("GET", "/users/{id}"))
+		require.Empty(t, r.DanglingRoutes())
+		require.Equal(t, hID, r.AddHandler(writeHandler("reused")))
+	})
+
+	main.Run("RefuseRoutes", func(t *testing.T) {
+		r := stdrouter.New()
+		r.RemoveHandlerMode = stdrouter.RefuseRoutes
+
+		hID := r.AddHandler(writeHandler("user"))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+		require.NoError(t, r.Add("POST", "/users/{id}", hID))
+
+		err := r.RemoveHandler(hID)
+		require.ErrorIs(t, err, stdrouter.ErrHandlerHasRoutes)
+		require.EqualError(t, err, "handler 1: handler has routes: 2")
+		require.Equal(t, "user", serve(r, "GET", "/users/1").Body.String())
+
+		require.Equal(t, 2, r.RemoveKey(hID))
+		require.NoError(t, r.RemoveHandler(hID))
+		require.Empty(t, r.DanglingRoutes())
+	})
+
+	main.Run("CascadeRoutes", func(t *testing.T) {
+		r := stdrouter.New()
+		r.RemoveHandlerMode = stdrouter.CascadeRoutes
+
+		hID := r.AddHandler(writeHandler("user"))
+		require.NoError(t, r.Add("GET", "/users/{id}", hID))
+		require.NoError(t, r.Add("POST", "/users/{id}", hID))
+
+		require.NoError(t, r.RemoveHandler(hID))
+		require.Empty(t, r.Routes())
+		require.Empty(t, r.DanglingRoutes())
+		require.Equal(t, hID, r.AddHandler(writeHandler("reused")))
+	})
+
+	main.Run("UnknownHandler", func(t *testing.T) {
+		r := stdrouter.New()
+		hID := r.AddHandler(writeHandler("user"))
+
+		require.EqualError(t, r.RemoveHandler(0), "handler 0 not found")
+		require.EqualError(t, r.RemoveHandler(-1), "handler -1 not found")
+		require.EqualError(t, r.RemoveHandler(hID+1), "handler 2 not found")
+
+		require.NoError(t, r.RemoveHandler(hID))
+		require.EqualError(t, r.RemoveHandler(hID), "handler 1 not found")
+
+		// the id was freed once, so it is handed out once
+		require.Equal(t, hID, r.AddHandler(writeHandler("a")))
+		require.NotEqual(t, hID, r.AddHandler(writeHandler("b")))
+	})
+}